@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"granny-pass/internal/provider/graph"
+	"granny-pass/internal/provider/keylog"
+	"granny-pass/internal/provider/layout"
+)
+
+const defaultPersonalMapFile = distMapDir + distMapFilePrefix + "_personal.json"
+
+// calibrate builds personal distance map from keystroke timing log
+func calibrate(args []string) {
+	var (
+		logFile, outFile, layoutFile string
+		useNormalizedKeyboard        bool
+		c                            keylog.Calibration
+	)
+
+	fs := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	fs.StringVar(&logFile, "log", "", "Keystroke log: .csv with records key,timestamp or .jsonl with records {\"key\": \"a\", \"timestamp\": 1700000000000}. Timestamp is in milliseconds or RFC3339")
+	fs.StringVar(&outFile, "out", defaultPersonalMapFile, "Output distance map, use it with -dm")
+	fs.StringVar(&layoutFile, "layout", defaultLayoutFile, "Keyboard layout file name, its graph distances fill unobserved bigrams")
+	fs.BoolVar(&useNormalizedKeyboard, "k", false, "Use normalized keyboard for graph distances")
+	fs.Float64Var(&c.MaxPause, "pause", keylog.DefaultMaxPause, "Longer intervals between keystrokes are pauses and are skipped, ms")
+	fs.IntVar(&c.MinCount, "min", keylog.DefaultMinCount, "Bigrams observed fewer times are filled from the graph distance")
+	_ = fs.Parse(args)
+
+	if logFile == "" {
+		fs.PrintDefaults()
+		log.Fatal("log file is not set")
+	}
+
+	events, err := keylog.ReadFile(logFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	l, err := layout.LoadFS(dataFS, layoutDir+layoutFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cm, err := NewCostModel(useNormalizedKeyboard, string(layout.ObjectiveHops), "", layout.Fitts{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dist, err := l.CostMap(cm)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	res, err := c.Calibrate(events, dist)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = graph.SaveToJson(graph.BigramDistanceArray(res.Costs), outFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("keystrokes: %d\nobserved bigrams: %d\nunobserved bigrams: time = %.1f + %.1f * hops ms\nsaved to: %s\n",
+		len(events), res.Observed, res.A, res.B, outFile)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"granny-pass/internal/provider/graph"
+	"granny-pass/internal/provider/layout"
+	"granny-pass/internal/provider/processor"
+)
+
+const defaultCompareLayouts = "qwerty.json,split.json,tv_abc.json,tv_qwerty.json"
+
+// comparison is the best password for one layout and connectivity mode with costs of the vocabulary
+type comparison struct {
+	layoutFile string
+	mode       string
+	password   string
+	pathLen    int
+	report     processor.PathReport
+	err        error
+}
+
+// compare runs the solver for every layout and connectivity mode with the same vocabulary and constraints
+// and prints the table sorted by the path length of the best password
+func compare(args []string) {
+	var (
+		minLen, maxLen, wordCnt, beamWidth int
+		layouts, modes, vocFile            string
+	)
+
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	fs.StringVar(&layouts, "layouts", defaultCompareLayouts, "Layout files separated by comma")
+	fs.StringVar(&modes, "modes", layout.ModeTask+","+layout.ModeNormalized, "Connectivity modes separated by comma: task, normalized")
+	fs.IntVar(&minLen, "min", defaultMinPasswordLen, "Provide minimum length of password")
+	fs.IntVar(&maxLen, "max", defaultMaxPasswordLen, "Provide maximum length of password")
+	fs.IntVar(&wordCnt, "cnt", defaultWordCnt, "Count of words")
+	fs.StringVar(&vocFile, "file", defaultVocabularyFile, "Vocabulary file name")
+	fs.IntVar(&beamWidth, "beam", defaultBeamWidth, "Count of partial passwords of every length kept by beam search, used with fingers of the layout or -cross hands")
+	kf := &keyboardFlags{}
+	kf.addCostFlags(fs)
+	_ = fs.Parse(args)
+
+	fmt.Printf("Comparing layouts: %s \n", layouts)
+	fmt.Printf(" min lenth: %d \n max lenth: %d \n count of words: %d \n", minLen, maxLen, wordCnt)
+	fmt.Printf(" vocabulary file: %s \n objective: %s \n\n", vocabularyDir+vocFile, kf.objective)
+
+	var res []comparison
+	for _, file := range strings.Split(layouts, ",") {
+		for _, mode := range strings.Split(modes, ",") {
+			f := *kf
+			f.layoutFile = strings.TrimSpace(file)
+			mode = strings.TrimSpace(mode)
+			switch mode {
+			case layout.ModeTask:
+			case layout.ModeNormalized:
+				f.useNormalizedKeyboard = true
+			default:
+				log.Fatalf("%v: %s", layout.ErrUnknownMode, mode)
+			}
+
+			c := compareLayout(&f, vocFile, minLen, maxLen, wordCnt, beamWidth)
+			c.mode = mode
+			res = append(res, c)
+		}
+	}
+
+	//the easiest layout first, failed ones at the end
+	sort.SliceStable(res, func(i, j int) bool {
+		if (res[i].err == nil) != (res[j].err == nil) {
+			return res[i].err == nil
+		}
+		return res[i].pathLen < res[j].pathLen
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "LAYOUT\tMODE\tPASSWORD\tPATH\tMEAN WORD PATH\tBIGRAM COST min/25%/50%/75%/max")
+	for _, c := range res {
+		if c.err != nil {
+			_, _ = fmt.Fprintf(w, "%s\t%s\terror: %v\t\t\t\n", c.layoutFile, c.mode, c.err)
+			continue
+		}
+
+		r := c.report
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.1f\t%d/%d/%d/%d/%d\n", c.layoutFile, c.mode, c.password, c.pathLen, r.MeanPathLen,
+			r.BigramQuantile(0), r.BigramQuantile(0.25), r.BigramQuantile(0.5), r.BigramQuantile(0.75), r.BigramQuantile(1))
+	}
+	_ = w.Flush()
+}
+
+// compareLayout finds the best password on the layout of flags and costs of words of the vocabulary
+func compareLayout(kf *keyboardFlags, vocFile string, minLen, maxLen, wordCnt, beamWidth int) comparison {
+	c := comparison{layoutFile: kf.layoutFile}
+
+	kb, err := kf.load()
+	if err != nil {
+		c.err = err
+		return c
+	}
+
+	p := processor.NewVocab(kb.bigrams, minLen, maxLen, uint8(wordCnt))
+	if err = SetKeyCosts(p, kb.layout, kb.cm.Mode, kb.dist, "", "", nil); err != nil {
+		c.err = err
+		return c
+	}
+
+	wm, err := p.ReadFS(dataFS, vocabularyDir+vocFile, true)
+	if err != nil {
+		c.err = err
+		return c
+	}
+
+	if err = graph.CheckCoverage(kb.dist, p.Letters(wm)); err != nil {
+		c.err = err
+		return c
+	}
+	c.report = p.PathReport(wm)
+
+	f, err := LayoutFingers(kb.layout, kb.cm.Cross)
+	if err != nil {
+		c.err = err
+		return c
+	}
+
+	var k processor.Features
+	if len(f) > 0 {
+		model, err := p.FingerModel(f)
+		if err != nil {
+			c.err = err
+			return c
+		}
+
+		kn, n, err := p.BeamSearch(model, wm, beamWidth)
+		if err != nil {
+			c.err = err
+			return c
+		}
+		k, c.pathLen = &kn, n
+	} else {
+		kn, n := p.MinChoice(p.KnapsackTable(wm))
+		k, c.pathLen = &kn, n
+	}
+
+	c.password = k.GetDescriptionWithSpace()
+	return c
+}
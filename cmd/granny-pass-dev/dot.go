@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"granny-pass/internal/provider/graph"
+	"granny-pass/internal/provider/layout"
+)
+
+// dot writes graph of the layout in Graphviz DOT format, the file can be edited and passed back with -layout
+func dot(args []string) {
+	var (
+		outFile, layoutFile   string
+		useNormalizedKeyboard bool
+	)
+
+	fs := flag.NewFlagSet("dot", flag.ExitOnError)
+	fs.StringVar(&outFile, "out", "", "Output .dot file, standard output by default. Render with: neato -Tsvg keyboard.dot")
+	fs.StringVar(&layoutFile, "layout", defaultLayoutFile, "Keyboard layout file name: .json or .dot")
+	fs.BoolVar(&useNormalizedKeyboard, "k", false, "Export normalized keyboard instead of keyboard from task")
+	_ = fs.Parse(args)
+
+	l, err := layout.LoadFS(dataFS, layoutDir+layoutFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mode := layout.ModeTask
+	if useNormalizedKeyboard {
+		mode = layout.ModeNormalized
+	}
+
+	g, err := l.Graph(mode, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if outFile == "" {
+		if err = graph.WriteDot(os.Stdout, g, l.Name); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	file, err := os.Create(outFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = graph.WriteDot(file, g, l.Name)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("saved to: %s\n", outFile)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"granny-pass/internal/provider/graph"
+)
+
+const defaultExportFile = distMapDir + distMapFilePrefix + "_export.csv"
+
+// export writes distances between keys as labelled matrix, it can be edited and passed back with -dm
+func export(args []string) {
+	var outFile string
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&outFile, "out", defaultExportFile, "Output matrix: .csv separated by comma or .tsv separated by tab")
+	kf := addKeyboardFlags(fs, defaultLayoutFile)
+	_ = fs.Parse(args)
+
+	kb, err := kf.load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dist, err := kb.allDistances()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = graph.SaveToCsv(dist, outFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("keys: %d\nsaved to: %s\n", len(dist), outFile)
+}
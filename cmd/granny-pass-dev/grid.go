@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"granny-pass/internal/provider/layout"
+)
+
+// grid writes layout of on-screen keyboard moved by D-pad of TV remote or game console
+func grid(args []string) {
+	var (
+		name, keys, rows, outFile string
+		width, press              int
+		wrap                      bool
+	)
+
+	fs := flag.NewFlagSet("grid", flag.ExitOnError)
+	fs.StringVar(&name, "name", "tv", "Layout name")
+	fs.StringVar(&keys, "keys", layout.GridAlphabet, "Keys in the order of rows, e.g. "+layout.GridQwerty+" for QWERTY grid")
+	fs.IntVar(&width, "width", 6, "Count of keys in the row, the last row may be shorter")
+	fs.StringVar(&rows, "rows", "", "Rows separated by comma replacing -keys and -width, e.g. "+strings.Join(layout.QwertyRows(), ","))
+	fs.BoolVar(&wrap, "wrap", false, "The cursor moves from the end of the row or column to its beginning")
+	fs.IntVar(&press, "ok", layout.DefaultGridPress, "Cost of pressing OK for every key")
+	fs.StringVar(&outFile, "out", "", "Output layout file, "+layoutDir+"<name>.json by default")
+	_ = fs.Parse(args)
+
+	var (
+		r   []string
+		err error
+	)
+	if rows != "" {
+		r = strings.Split(rows, ",")
+	} else {
+		r, err = layout.GridRows(keys, width)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	l, err := layout.NewGrid(name, r, wrap, press)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if outFile == "" {
+		outFile = layoutDir + name + ".json"
+	}
+	if err = l.Save(outFile); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%s\nsaved to: %s\n", strings.Join(r, "\n"), outFile)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"granny-pass/internal/provider/layout"
+)
+
+// importLayout converts keyboard description of other programs to the layout file
+func importLayout(args []string) {
+	var inFile, format, variant, geometry, name, outFile string
+
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.StringVar(&inFile, "in", "", "Input file")
+	fs.StringVar(&format, "format", "kle", "Input format: kle - raw data of keyboard-layout-editor.com, "+
+		"xkb - XKB symbols file, e.g. /usr/share/X11/xkb/symbols/de, klc - Microsoft Keyboard Layout Creator file")
+	fs.StringVar(&variant, "variant", "", "Variant of XKB symbols file, the default one if not set")
+	fs.StringVar(&geometry, "geometry", "", "Keyboard for xkb and klc formats: ansi or iso, by the layout if not set")
+	fs.StringVar(&name, "name", "", "Layout name, the name of the input file by default")
+	fs.StringVar(&outFile, "out", "", "Output layout file, "+layoutDir+"<name>.json by default")
+	_ = fs.Parse(args)
+
+	if inFile == "" {
+		log.Fatal("input file is not set")
+	}
+
+	var (
+		l   *layout.Layout
+		err error
+	)
+	switch format {
+	case "kle":
+		l, err = parseImported(inFile, func(data []byte, name string) (*layout.Layout, error) {
+			return layout.ParseKLE(data, name)
+		})
+	case "klc":
+		l, err = parseImported(inFile, func(data []byte, name string) (*layout.Layout, error) {
+			return layout.ParseKLC(data, name, geometry)
+		})
+	case "xkb":
+		//included files are searched in the directory of the symbols file
+		spec := filepath.Base(inFile)
+		if variant != "" {
+			spec += "(" + variant + ")"
+		}
+		l, err = layout.LoadXKB(os.DirFS(filepath.Dir(inFile)), spec, geometry)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if name != "" {
+		l.Name = name
+	}
+
+	if outFile == "" {
+		outFile = layoutDir + l.Name + ".json"
+	}
+	if err = l.Save(outFile); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("keys: %d\nsaved to: %s\n", len(l.Keys), outFile)
+}
+
+// parseImported parses the input file named by the file name without extension
+func parseImported(inFile string, parse func(data []byte, name string) (*layout.Layout, error)) (*layout.Layout, error) {
+	data, err := os.ReadFile(inFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(data, strings.TrimSuffix(filepath.Base(inFile), filepath.Ext(inFile)))
+}
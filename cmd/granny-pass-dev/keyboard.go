@@ -0,0 +1,265 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"granny-pass/internal/provider/graph"
+	"granny-pass/internal/provider/layout"
+)
+
+// keyboardFlags describe the keyboard and the cost of moves, they are shared by commands
+type keyboardFlags struct {
+	layoutFile            string
+	objective             string
+	cross                 string
+	dmFile                string
+	exclude               string
+	useNormalizedKeyboard bool
+	fitts                 layout.Fitts
+}
+
+// keyboard is the loaded layout with costs of moves
+type keyboard struct {
+	layout *layout.Layout
+	cm     layout.CostModel
+	// bigrams - distances between letters for the processor
+	bigrams []int
+	// dist - distances between letters of the layout or between keys of distance map file,
+	// distances between all keys of the layout are calculated by allDistances
+	dist map[string]map[string]int
+	// full - dist contains all keys
+	full bool
+}
+
+// allDistances returns distances between all keys, e.g. for start and end keys which are not letters.
+// They are calculated if letters were read from the cache
+func (k *keyboard) allDistances() (map[string]map[string]int, error) {
+	if k.full {
+		return k.dist, nil
+	}
+
+	dist, err := k.layout.CostMap(k.cm)
+	if err != nil {
+		return nil, err
+	}
+	k.dist, k.full = dist, true
+	return dist, nil
+}
+
+func addKeyboardFlags(fs *flag.FlagSet, defaultLayout string) *keyboardFlags {
+	f := &keyboardFlags{}
+
+	fs.BoolVar(&f.useNormalizedKeyboard, "k", false, "Use normalized keyboard - natural movement of one-finger typing method. By default will use keyboard from the task: only horizontal and vertical connections of buttons")
+	fs.StringVar(&f.layoutFile, "layout", defaultLayout, "Keyboard layout file name: .json or graph in .dot format made by dot command")
+	f.addCostFlags(fs)
+	fs.StringVar(&f.dmFile, "dm", "", "Distance map file replacing -objective for letters: .json made by calibrate command or labelled matrix .csv/.tsv made by export command")
+	fs.StringVar(&f.exclude, "exclude", "", "Broken or invisible keys separated by comma, e.g. e,q. Paths go around them, words with them are dropped from the vocabulary")
+	return f
+}
+
+// addCostFlags adds flags of the cost model without the layout, e.g. for commands comparing layouts
+func (f *keyboardFlags) addCostFlags(fs *flag.FlagSet) {
+	fs.StringVar(&f.objective, "objective", string(layout.ObjectiveHops), "What to minimize: hops - moves between neighbour keys, distance - distance between key centers in mm, time - movement time in ms by Fitts's law")
+	fs.StringVar(&f.cross, "cross", string(layout.CrossFinger), "Moves between halves of split keyboard: finger - one finger crosses the gap, hands - every half is typed by its own hand (keys need hand or cluster with hand)")
+	fs.Float64Var(&f.fitts.A, "fitts-a", layout.DefaultFittsA, "Constant a of Fitts's law MT = a + b*log2(D/W+1), ms")
+	fs.Float64Var(&f.fitts.B, "fitts-b", layout.DefaultFittsB, "Constant b of Fitts's law MT = a + b*log2(D/W+1), ms")
+}
+
+// excludedKeys returns names of excluded keys
+func (f *keyboardFlags) excludedKeys() []string {
+	if f.exclude == "" {
+		return nil
+	}
+
+	keys := strings.Split(f.exclude, ",")
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+	return keys
+}
+
+func (f *keyboardFlags) print() {
+	fmt.Printf(" layout file: %s \n", layoutDir+f.layoutFile)
+	if f.useNormalizedKeyboard {
+		fmt.Println(" with normalized keyboard")
+	} else {
+		fmt.Println(" with keyboard from task")
+	}
+	if f.dmFile != "" {
+		fmt.Printf(" distance map: %s \n", f.dmFile)
+	} else {
+		fmt.Printf(" objective: %s \n", f.objective)
+	}
+	if f.cross == string(layout.CrossHands) {
+		fmt.Println(" every half is typed by its own hand")
+	}
+	if f.exclude != "" {
+		fmt.Printf(" excluded keys: %s \n", f.exclude)
+	}
+}
+
+func (f *keyboardFlags) load() (*keyboard, error) {
+	k, err := f.loadLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	if f.dmFile != "" {
+		//start and end keys should be letters
+		k.dist, err = LoadDistanceMap(f.dmFile)
+		if err != nil {
+			return nil, err
+		}
+		k.bigrams = graph.BigramDistanceArray(k.dist)
+		k.full = true
+		return k, nil
+	}
+
+	k.bigrams, k.dist, err = GetBigramDistanceMap(k.layout, k.cm)
+	if err != nil {
+		return nil, err
+	}
+	if k.dist != nil {
+		k.full = true
+		return k, nil
+	}
+
+	//only letters of the layout are covered
+	letters := graph.BigramDistanceMap(k.bigrams)
+	k.dist = make(map[string]map[string]int, len(letters))
+	for _, k1 := range k.layout.Keys {
+		if row, ok := letters[k1.Name]; ok {
+			k.dist[k1.Name] = make(map[string]int, len(row))
+			for _, k2 := range k.layout.Keys {
+				if d, ok := row[k2.Name]; ok {
+					k.dist[k1.Name][k2.Name] = d
+				}
+			}
+		}
+	}
+	return k, nil
+}
+
+// loadLayout loads the layout without excluded keys and the cost model, distances are not calculated
+func (f *keyboardFlags) loadLayout() (*keyboard, error) {
+	var err error
+
+	k := &keyboard{}
+	k.layout, err = layout.LoadFS(dataFS, layoutDir+f.layoutFile)
+	if err != nil {
+		return nil, err
+	}
+
+	k.layout, err = k.layout.Exclude(f.excludedKeys()...)
+	if err != nil {
+		return nil, err
+	}
+
+	k.cm, err = NewCostModel(f.useNormalizedKeyboard, f.objective, f.cross, f.fitts)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// LoadDistanceMap reads distances from .json array or from labelled .csv/.tsv matrix
+func LoadDistanceMap(filename string) (map[string]map[string]int, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".tsv":
+		return graph.ReadFromCsv(filename)
+	default:
+		m, err := graph.ReadFromJson(filename)
+		if err != nil {
+			return nil, err
+		}
+		return graph.BigramDistanceMap(m), nil
+	}
+}
+
+// NewCostModel returns cost model of the keyboard for command line flags
+func NewCostModel(useNormalizedKeyboard bool, objective, cross string, fitts layout.Fitts) (layout.CostModel, error) {
+	o, err := layout.ParseObjective(objective)
+	if err != nil {
+		return layout.CostModel{}, err
+	}
+
+	c, err := layout.ParseCross(cross)
+	if err != nil {
+		return layout.CostModel{}, err
+	}
+
+	cm := layout.CostModel{
+		Objective:  o,
+		Mode:       layout.ModeTask,
+		MaxPathLen: maxKeyboardPathLen,
+		Fitts:      fitts,
+		Cross:      c,
+	}
+	if useNormalizedKeyboard {
+		cm.Mode = layout.ModeNormalized
+	}
+	return cm, nil
+}
+
+// GetBigramDistanceMap returns distances between letters. Maps of bundled layouts are precomputed and embedded
+// into the binary, other maps are cached in the cache directory with meta of the layout and the cost model.
+// The file is named by the layout hash too, so changed layouts and layouts without excluded keys do not
+// overwrite each other. If the map is calculated, distances between all keys are returned too, otherwise nil
+func GetBigramDistanceMap(l *layout.Layout, cm layout.CostModel) ([]int, map[string]map[string]int, error) {
+	hash, err := l.Hash()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := graph.NewCacheMeta(l.Name, cm.Mode, cm.String(), hash)
+	name := distMapFilePrefix + "_" + l.Name + "_" + cm.Mode + "_" + string(cm.Objective)
+	if cm.Cross == layout.CrossHands {
+		name += "_" + string(cm.Cross)
+	}
+	name += "_" + hash[:distMapHashLen] + ".json"
+
+	m, err := graph.ReadCacheFS(dataFS, distMapDir+name, meta)
+	if err == nil {
+		return m, nil, nil
+	}
+
+	//the cache is optional, the map is still usable without it
+	dir, dirErr := cacheDir()
+	if dirErr == nil {
+		if m, err = graph.ReadCache(filepath.Join(dir, name), meta); err == nil {
+			return m, nil, nil
+		}
+	}
+
+	dist, err := l.CostMap(cm)
+	if err != nil {
+		return nil, nil, err
+	}
+	m = graph.BigramDistanceArray(dist)
+
+	if dirErr != nil {
+		log.Printf("can not save distance map cache: %v", dirErr)
+	} else if err = graph.SaveCache(m, meta, filepath.Join(dir, name)); err != nil {
+		log.Printf("can not save distance map cache: %v", err)
+	}
+	return m, dist, nil
+}
+
+// cacheDir returns the directory of cached distance maps: cacheDirEnv if it is set,
+// e.g. distanceMaps to rebuild bundled maps, or granny-pass in the user cache directory
+func cacheDir() (string, error) {
+	if dir := os.Getenv(cacheDirEnv); dir != "" {
+		return dir, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "granny-pass"), nil
+}
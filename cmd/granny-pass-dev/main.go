@@ -5,36 +5,91 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
+	grannypass "granny-pass"
 	"granny-pass/internal/provider/graph"
+	"granny-pass/internal/provider/layout"
 	"granny-pass/internal/provider/processor"
 )
 
 const (
 	maxKeyboardPathLen = 20
 	vocabularyDir      = "vocabularies/"
+	layoutDir          = "layouts/"
 	distMapDir         = "distanceMaps/"
 	distMapFilePrefix  = "dm"
+	distMapHashLen     = 8
+	cacheDirEnv        = "GRANNY_PASS_CACHE"
 
 	defaultMinPasswordLen = 20
 	defaultMaxPasswordLen = 24
 	defaultWordCnt        = 4
 	defaultVocabularyFile = "short.txt"
+	defaultBeamWidth      = 1000
+	defaultLayoutFile     = "qwerty.json"
+
+	enterKey = "enter"
 )
 
+// dataFS contains layouts, distance maps and vocabularies, files in the working directory override bundled ones
+var dataFS = grannypass.FS(".")
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "calibrate":
+			calibrate(os.Args[2:])
+			return
+		case "export":
+			export(os.Args[2:])
+			return
+		case "dot":
+			dot(os.Args[2:])
+			return
+		case "grid":
+			grid(os.Args[2:])
+			return
+		case "pin":
+			pinCode(os.Args[2:])
+			return
+		case "import":
+			importLayout(os.Args[2:])
+			return
+		case "compare":
+			compare(os.Args[2:])
+			return
+		case "optimize":
+			optimizeLayout(os.Args[2:])
+			return
+		case "vocab":
+			vocabCommand(os.Args[2:])
+			return
+		}
+	}
+
 	var (
-		minLen, maxLen, wordCnt     int
-		useNormalizedKeyboard, help bool
-		vocFile                     string
+		minLen, maxLen, wordCnt, beamWidth int
+		help, swipe                        bool
+		turnPenalty                        float64
+		vocFile, startKey, endKey          string
+		pressCost, fingers                 string
 	)
 
 	flag.BoolVar(&help, "help", false, "Help")
 	flag.IntVar(&minLen, "min", defaultMinPasswordLen, "Provide minimum length of password")
 	flag.IntVar(&maxLen, "max", defaultMaxPasswordLen, "Provide maximum length of password")
 	flag.IntVar(&wordCnt, "cnt", defaultWordCnt, "Count of words")
-	flag.BoolVar(&useNormalizedKeyboard, "k", false, "Use normalized keyboard - natural movement of one-finger typing method. By default will use keyboard from the task: only horizontal and vertical connections of buttons")
 	flag.StringVar(&vocFile, "file", defaultVocabularyFile, "Vocabulary file name. Should consist of low-case words, no numbers, no special symbols. New line separator")
+	flag.StringVar(&startKey, "start", "", "Key where the finger rests before typing, e.g. g or the last letter of the username. Its distance to the first letter is added to the path")
+	flag.StringVar(&endKey, "end", "", "Key pressed after the password, e.g. "+enterKey+". Distance from the last letter to it is added to the path")
+	flag.StringVar(&pressCost, "press", "", "Cost of pressing hard-to-reach keys, added per keystroke, e.g. q=2,p=2,z=1")
+	flag.StringVar(&fingers, "fingers", "", "Type with several fingers: home key and keys of every finger, e.g. two index fingers f:qwertasdfgzxcvb,j:yuiophjklnm. Home key may be omitted: :qwert. Home keys replace -start. By default fingers of the layout keys are used if they are set")
+	flag.IntVar(&beamWidth, "beam", defaultBeamWidth, "Count of partial passwords of every length kept by beam search, used with -fingers")
+	flag.BoolVar(&swipe, "swipe", false, "Swipe typing on the touchscreen, e.g. -layout touch.json: the cost of the word is the length of the gesture through key centers in mm, the objective is distance")
+	flag.Float64Var(&turnPenalty, "turn", defaultTurnPenalty, "Cost of the U-turn of the swipe in key units, turns sharper than 90 degrees cost its part by the angle")
+	kf := addKeyboardFlags(flag.CommandLine, defaultLayoutFile)
 
 	flag.Parse()
 
@@ -45,166 +100,230 @@ func main() {
 		fmt.Println("Generating password for a grandmother. Parameters:")
 		fmt.Printf(" min lenth: %d \n max lenth: %d \n count of words: %d \n", minLen, maxLen, wordCnt)
 		fmt.Printf(" vocabulary file: %s \n", vocabularyDir+vocFile)
-		if useNormalizedKeyboard {
-			fmt.Println(" with normalized keyboard")
-		} else {
-			fmt.Println(" with keyboard from task")
+		if swipe {
+			//gaps between words are straight moves of the lifted finger
+			kf.objective = string(layout.ObjectiveDistance)
+			fmt.Printf(" swipe typing, U-turn cost: %g \n", turnPenalty)
+		}
+		kf.print()
+		if startKey != "" {
+			fmt.Printf(" start key: %s \n", startKey)
+		}
+		if endKey != "" {
+			fmt.Printf(" end key: %s \n", endKey)
+		}
+		if pressCost != "" {
+			fmt.Printf(" press cost: %s \n", pressCost)
+		}
+		if fingers != "" {
+			fmt.Printf(" fingers: %s \n", fingers)
+		}
+
+		press, err := ParsePressCost(pressCost)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		kb, err := kf.load()
+		if err != nil {
+			log.Fatal(err)
 		}
 
-		m, err := GetBigramDistanceMap(useNormalizedKeyboard)
+		f, err := ParseFingers(fingers)
 		if err != nil {
 			log.Fatal(err)
 		}
+		if fingers == "" {
+			f, err = LayoutFingers(kb.layout, kb.cm.Cross)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		//the gesture is drawn by one finger, the finger model would ignore it
+		if swipe && len(f) > 0 {
+			log.Fatal("-swipe can not be used with several fingers: -fingers, fingers of the layout keys or -cross hands")
+		}
+
+		p := processor.NewVocab(kb.bigrams, minLen, maxLen, uint8(wordCnt))
+		p.SetExcludedKeys(kf.excludedKeys())
+		if swipe {
+			p.SetGesture(NewGesture(kb.layout, turnPenalty))
+		}
+
+		//start and end keys may be not letters
+		dist := kb.dist
+		if startKey != "" || endKey != "" {
+			dist, err = kb.allDistances()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
 
-		p := processor.NewVocab(m, minLen, maxLen, uint8(wordCnt))
+		err = SetKeyCosts(p, kb.layout, kb.cm.Mode, dist, startKey, endKey, press)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		wm, err := p.ReadFile(vocabularyDir+vocFile, true)
+		wm, err := p.ReadFS(dataFS, vocabularyDir+vocFile, true)
 		if err != nil {
 			log.Fatal(err)
 		}
-		kt := p.KnapsackTable(wm)
-		k, pathLen := p.MinChoice(kt)
+
+		if r := p.Exclusion(); r.Dropped > 0 {
+			fmt.Printf(" dropped words with excluded keys: %d of %d (%.1f%%), by key: %v \n", r.Dropped, r.Words, r.Lost()*100, r.ByKey)
+		}
+
+		err = graph.CheckCoverage(kb.dist, p.Letters(wm))
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		var (
+			k       processor.Features
+			pathLen int
+		)
+
+		if len(f) == 0 {
+			kt := p.KnapsackTable(wm)
+			kn, n := p.MinChoice(kt)
+			k, pathLen = &kn, n
+		} else {
+			//cost depends on positions of all fingers, so knapsack table can not be used
+			model, err := p.FingerModel(f)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			kn, n, err := p.BeamSearch(model, wm, beamWidth)
+			if err != nil {
+				log.Fatal(err)
+			}
+			k, pathLen = &kn, n
+		}
+
 		fmt.Printf("\nRESULT:\n%s \n used words: %s, lenth: %d, path lenth: %d\n", k.GetDescription(), k.GetDescriptionWithSpace(), len(k.GetDescription()), pathLen)
 	}
 }
 
-func GetBigramDistanceMap(useNormalizedKeyboard bool) ([]int, error) {
-	var (
-		err      error
-		m        []int
-		filename string
-	)
+// SetKeyCosts passes distances from the start key and to the end key and press costs
+// of the layout and of the keys to the processor
+func SetKeyCosts(p processor.NewProcessor, l *layout.Layout, mode string, dist map[string]map[string]int, startKey, endKey string, press map[string]int) error {
+	if startKey == "" && endKey == "" && len(press) == 0 && l.Press == 0 {
+		return nil
+	}
+
+	g, err := l.Graph(mode, press)
+	if err != nil {
+		return err
+	}
 
-	filename = distMapDir + distMapFilePrefix + ".json"
-	if useNormalizedKeyboard {
-		filename = distMapDir + distMapFilePrefix + "_norm.json"
+	weights, err := g.VertexWeights()
+	if err != nil {
+		return err
+	}
+	if len(press) > 0 || l.Press > 0 {
+		p.SetPressCost(graph.KeyWeightArray(weights))
 	}
 
-	if _, err = os.Stat(filename); err == nil {
-		m, err = graph.ReadFromJson(filename)
+	if startKey != "" {
+		a, err := graph.FromKeyDistanceArray(dist, startKey)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("start key: %w", err)
 		}
-	} else {
-		dist := PrepareDistMap(useNormalizedKeyboard)
-		m = graph.BigramDistanceArray(dist)
-		err = graph.SaveToJson(m, filename)
+		p.SetStartKey(a)
+	}
+
+	if endKey != "" {
+		a, err := graph.ToKeyDistanceArray(dist, endKey)
 		if err != nil {
-			fmt.Printf("%v", err)
+			return fmt.Errorf("end key: %w", err)
 		}
+		//the end key is pressed too
+		for i := range a {
+			a[i] += weights[endKey]
+		}
+		p.SetEndKey(a)
 	}
-	return m, nil
+	return nil
 }
 
-func PrepareDistMap(useNormalizedKeyboard bool) map[string]map[string]int {
-	hash := func(v graph.Vertex) string {
-		return v.Name
-	}
-	g := graph.New(hash)
-
-	//add all key buttons
-	for r := 'a'; r <= 'z'; r++ {
-		_ = g.AddVertex(graph.Vertex{Name: string(r)})
-	}
-
-	//add all connections, weight=1
-	//row1
-	_ = g.AddEdge("q", "w")
-	_ = g.AddEdge("w", "e")
-	_ = g.AddEdge("e", "r")
-	_ = g.AddEdge("r", "t")
-	_ = g.AddEdge("t", "y")
-	_ = g.AddEdge("y", "u")
-	_ = g.AddEdge("u", "i")
-	_ = g.AddEdge("i", "o")
-	_ = g.AddEdge("o", "p")
-
-	//row2
-	_ = g.AddEdge("a", "s")
-	_ = g.AddEdge("s", "d")
-	_ = g.AddEdge("d", "f")
-	_ = g.AddEdge("f", "g")
-	_ = g.AddEdge("g", "h")
-	_ = g.AddEdge("h", "j")
-	_ = g.AddEdge("j", "k")
-	_ = g.AddEdge("k", "l")
-
-	//row3
-	_ = g.AddEdge("z", "x")
-	_ = g.AddEdge("x", "c")
-	_ = g.AddEdge("c", "v")
-	_ = g.AddEdge("v", "b")
-	_ = g.AddEdge("b", "n")
-	_ = g.AddEdge("n", "m")
-
-	if useNormalizedKeyboard {
-		//natural movement of one-finger typing method
-		_ = g.AddEdge("q", "a")
-		_ = g.AddEdge("w", "a")
-		_ = g.AddEdge("w", "s")
-		_ = g.AddEdge("e", "s")
-		_ = g.AddEdge("e", "d")
-		_ = g.AddEdge("r", "d")
-		_ = g.AddEdge("r", "f")
-		_ = g.AddEdge("t", "f")
-		_ = g.AddEdge("t", "g")
-		_ = g.AddEdge("y", "g")
-		_ = g.AddEdge("y", "h")
-		_ = g.AddEdge("u", "h")
-		_ = g.AddEdge("u", "j")
-		_ = g.AddEdge("i", "j")
-		_ = g.AddEdge("i", "k")
-		_ = g.AddEdge("o", "k")
-		_ = g.AddEdge("o", "l")
-		_ = g.AddEdge("p", "l")
-
-		_ = g.AddEdge("a", "z")
-		_ = g.AddEdge("s", "z")
-		_ = g.AddEdge("s", "x")
-		_ = g.AddEdge("d", "x")
-		_ = g.AddEdge("d", "c")
-		_ = g.AddEdge("f", "c")
-		_ = g.AddEdge("f", "v")
-		_ = g.AddEdge("g", "v")
-		_ = g.AddEdge("g", "b")
-		_ = g.AddEdge("h", "b")
-		_ = g.AddEdge("h", "n")
-		_ = g.AddEdge("j", "n")
-		_ = g.AddEdge("j", "m")
-		_ = g.AddEdge("k", "m")
-	} else {
-		//vertical only
-		//left part
-		_ = g.AddEdge("q", "a")
-		_ = g.AddEdge("a", "z")
+// ParsePressCost parses list of key=cost pairs separated by comma
+func ParsePressCost(s string) (map[string]int, error) {
+	res := make(map[string]int)
+	if s == "" {
+		return res, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("wrong press cost %q, expected key=cost", pair)
+		}
 
-		_ = g.AddEdge("w", "s")
-		_ = g.AddEdge("s", "x")
+		cost, err := strconv.Atoi(value)
+		if err != nil || cost < 0 {
+			return nil, fmt.Errorf("wrong press cost %q, expected non-negative integer", pair)
+		}
+		res[key] = cost
+	}
+	return res, nil
+}
 
-		_ = g.AddEdge("e", "d")
-		_ = g.AddEdge("d", "c")
+// ParseFingers parses list of fingers separated by comma, every finger is home:keys
+func ParseFingers(s string) ([]processor.Finger, error) {
+	var res []processor.Finger
+	if s == "" {
+		return res, nil
+	}
 
-		_ = g.AddEdge("r", "f")
-		_ = g.AddEdge("f", "v")
+	for _, f := range strings.Split(s, ",") {
+		home, keys, ok := strings.Cut(strings.TrimSpace(f), ":")
+		if !ok || keys == "" || len(home) > 1 {
+			return nil, fmt.Errorf("wrong finger %q, expected home:keys", f)
+		}
 
-		_ = g.AddEdge("t", "g")
-		_ = g.AddEdge("g", "b")
+		finger := processor.Finger{Keys: keys}
+		if home != "" {
+			finger.Home = home[0]
+		}
+		res = append(res, finger)
+	}
+	return res, nil
+}
 
-		//right part
-		_ = g.AddEdge("y", "h")
-		_ = g.AddEdge("h", "n")
+// LayoutFingers returns fingers of the layout keys, one finger per hand with CrossHands if keys
+// have no fingers, or nil for one-finger typing
+func LayoutFingers(l *layout.Layout, cross layout.Cross) ([]processor.Finger, error) {
+	keys, err := l.Fingers()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		if cross == layout.CrossHands {
+			return HandFingers(l)
+		}
+		return nil, nil
+	}
 
-		_ = g.AddEdge("u", "j")
-		_ = g.AddEdge("j", "m")
+	res := make([]processor.Finger, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, processor.Finger{Keys: k})
+	}
+	return res, nil
+}
 
-		_ = g.AddEdge("i", "k")
+// HandFingers returns one finger per hand of the layout, the first move of every hand is free
+func HandFingers(l *layout.Layout) ([]processor.Finger, error) {
+	hands, err := l.Hands()
+	if err != nil {
+		return nil, err
+	}
 
-		_ = g.AddEdge("o", "l")
+	res := make([]processor.Finger, 0, len(hands))
+	for _, hand := range []string{graph.HandLeft, graph.HandRight} {
+		if keys, ok := hands[hand]; ok {
+			res = append(res, processor.Finger{Keys: keys})
+		}
 	}
-	m, _ := g.WFI(maxKeyboardPathLen)
-	return m
+	return res, nil
 }
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"granny-pass/internal/provider/layout"
+	"granny-pass/internal/provider/optimize"
+)
+
+const defaultOptimizeLayoutFile = "tv_abc.json"
+
+// optimizeLayout places letters on keys of the layout, e.g. on-screen grid, so words of the vocabulary
+// or passphrases are typed with the shortest path, and writes the layout file
+func optimizeLayout(args []string) {
+	var (
+		vocFile, keys, name, outFile string
+		iterations                   int
+		temperature                  float64
+		seed                         int64
+	)
+
+	fs := flag.NewFlagSet("optimize", flag.ExitOnError)
+	fs.StringVar(&vocFile, "file", defaultVocabularyFile, "Vocabulary file name or passphrases, one per line, spaces are not typed")
+	fs.StringVar(&keys, "keys", "", "Keys to move, e.g. aeiou, all letters of the layout by default")
+	fs.IntVar(&iterations, "iter", optimize.DefaultIterations, "Count of tried swaps of keys")
+	fs.Float64Var(&temperature, "temp", 0, "Initial temperature of annealing in units of the cost, chosen by random swaps if 0")
+	fs.Int64Var(&seed, "seed", optimize.DefaultSeed, "Seed of the random generator")
+	fs.StringVar(&name, "name", "", "Layout name, <layout>_opt by default")
+	fs.StringVar(&outFile, "out", "", "Output layout file, "+layoutDir+"<name>.json by default")
+	kf := addKeyboardFlags(fs, defaultOptimizeLayoutFile)
+	_ = fs.Parse(args)
+
+	kb, err := kf.loadLayout()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if kf.dmFile != "" {
+		kb.dist, err = LoadDistanceMap(kf.dmFile)
+	} else {
+		kb.dist, err = kb.layout.CostMap(kb.cm)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	texts, err := readTexts(vocabularyDir + vocFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	a, err := optimize.NewAnnealer(kb.dist, movedKeys(kb.layout, keys), texts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	a.Iterations, a.Temperature, a.Seed = iterations, temperature, seed
+
+	res, err := a.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	l, err := kb.layout.Rename(res.Placement)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	l.Name = name
+	if l.Name == "" {
+		l.Name = kb.layout.Name + "_opt"
+	}
+	if outFile == "" {
+		outFile = layoutDir + l.Name + ".json"
+	}
+	if err = l.Save(outFile); err != nil {
+		log.Fatal(err)
+	}
+
+	initial := optimize.Result{Cost: res.Initial, Texts: res.Texts}
+	fmt.Printf("texts: %d, skipped with other characters: %d\n", res.Texts, a.Skipped)
+	fmt.Printf("path length: %d -> %d, average: %.2f -> %.2f\n", res.Initial, res.Cost, initial.Average(), res.Average())
+	fmt.Printf("%s\nsaved to: %s\n", strings.Join(layoutRows(l), "\n"), outFile)
+}
+
+// movedKeys returns keys of the string or letters of the layout if it is empty
+func movedKeys(l *layout.Layout, keys string) []string {
+	var res []string
+	if keys != "" {
+		for _, r := range keys {
+			res = append(res, string(r))
+		}
+		return res
+	}
+
+	for _, k := range l.Keys {
+		if len(k.Name) == 1 && k.Name[0] >= 'a' && k.Name[0] <= 'z' {
+			res = append(res, k.Name)
+		}
+	}
+	return res
+}
+
+// readTexts reads lines of the file, empty lines are skipped
+func readTexts(filename string) ([]string, error) {
+	file, err := dataFS.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var res []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if s := strings.ToLower(strings.TrimSpace(scanner.Text())); s != "" {
+			res = append(res, s)
+		}
+	}
+	return res, scanner.Err()
+}
+
+// layoutRows returns names of keys by rows from left to right
+func layoutRows(l *layout.Layout) []string {
+	keys := append([]layout.Key(nil), l.Keys...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].Row() != keys[j].Row() {
+			return keys[i].Row() < keys[j].Row()
+		}
+		return keys[i].X < keys[j].X
+	})
+
+	var res []string
+	for i, k := range keys {
+		if i == 0 || k.Row() != keys[i-1].Row() {
+			res = append(res, "")
+		}
+		if res[len(res)-1] != "" {
+			res[len(res)-1] += " "
+		}
+		res[len(res)-1] += k.Name
+	}
+	return res
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+
+	"granny-pass/internal/provider/pin"
+)
+
+const defaultPinLayoutFile = "phone.json"
+
+// pinCode prints numeric codes with low travel on the keypad, trivial codes (repeats, lines, sequences) are penalized
+func pinCode(args []string) {
+	var (
+		length, cnt, penalty int
+		random               bool
+	)
+
+	fs := flag.NewFlagSet("pin", flag.ExitOnError)
+	fs.IntVar(&length, "len", 4, fmt.Sprintf("Length of the code, up to %d", pin.MaxLength))
+	fs.IntVar(&cnt, "cnt", 10, "Count of the best codes")
+	fs.IntVar(&penalty, "penalty", pin.DefaultPenalty, "Cost added for every trivial pattern: repeated digit, straight line of keys, sequence of digits, fewer than 3 distinct digits")
+	fs.BoolVar(&random, "random", false, "Print one of the best codes chosen at random, so the code can not be guessed by running the program")
+	kf := addKeyboardFlags(fs, defaultPinLayoutFile)
+	_ = fs.Parse(args)
+
+	kb, err := kf.loadLayout()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if kf.dmFile != "" {
+		kb.dist, err = LoadDistanceMap(kf.dmFile)
+	} else {
+		kb.dist, err = kb.layout.CostMap(kb.cm)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pos := make(map[string]pin.Point, len(kb.layout.Keys))
+	for _, k := range kb.layout.Keys {
+		x, y := k.Center()
+		pos[k.Name] = pin.Point{X: x, Y: y}
+	}
+
+	g, err := pin.NewGenerator(kb.dist, pos, penalty)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	codes, err := g.Best(length, cnt)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if random {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(codes))))
+		if err != nil {
+			log.Fatal(err)
+		}
+		codes = codes[n.Int64() : n.Int64()+1]
+	}
+
+	for _, c := range codes {
+		fmt.Printf("%s travel: %d, cost: %d", c.PIN, c.Travel, c.Cost)
+		if len(c.Patterns) > 0 {
+			fmt.Printf(", patterns: %v", c.Patterns)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"granny-pass/internal/provider/layout"
+	"granny-pass/internal/provider/processor"
+)
+
+const defaultTurnPenalty = 1.0
+
+// NewGesture returns swipe cost of the layout in millimetres, turnPenalty is the cost of the U-turn in key units
+func NewGesture(l *layout.Layout, turnPenalty float64) *processor.Gesture {
+	unit := l.UnitMM()
+
+	g := &processor.Gesture{
+		Centers:     make(map[uint8]processor.Point, len(l.Keys)),
+		TurnPenalty: turnPenalty * unit,
+		SharpAngle:  processor.DefaultSharpAngle,
+	}
+
+	for _, k := range l.Keys {
+		if len(k.Name) != 1 || k.Name[0] < 'a' || k.Name[0] > 'z' {
+			continue
+		}
+		x, y := k.Center()
+		g.Centers[k.Name[0]] = processor.Point{X: x * unit, Y: y * unit}
+	}
+	return g
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"granny-pass/internal/provider/processor"
+)
+
+const defaultCheapestCnt = 3
+
+// vocabCommand runs commands of vocabularies: stats
+func vocabCommand(args []string) {
+	if len(args) == 0 || args[0] != "stats" {
+		log.Fatal("expected command: vocab stats")
+	}
+	vocabStats(args[1:])
+}
+
+// vocabStats prints statistics of the vocabulary on the layout as text or JSON
+func vocabStats(args []string) {
+	var (
+		vocFile     string
+		cheapestCnt int
+		asJSON      bool
+	)
+
+	fs := flag.NewFlagSet("vocab stats", flag.ExitOnError)
+	fs.StringVar(&vocFile, "file", defaultVocabularyFile, "Vocabulary file name")
+	fs.IntVar(&cheapestCnt, "top", defaultCheapestCnt, "Count of the cheapest words of every length")
+	fs.BoolVar(&asJSON, "json", false, "Print statistics as JSON")
+	kf := addKeyboardFlags(fs, defaultLayoutFile)
+	_ = fs.Parse(args)
+
+	kb, err := kf.load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := processor.NewVocab(kb.bigrams, 0, 0, 0)
+	p.SetExcludedKeys(kf.excludedKeys())
+	if err = SetKeyCosts(p, kb.layout, kb.cm.Mode, kb.dist, "", "", nil); err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := p.Stats(dataFS, vocabularyDir+vocFile, cheapestCnt)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(s); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	fmt.Printf("vocabulary file: %s \n", vocabularyDir+vocFile)
+	kf.print()
+	fmt.Printf("words: %d, rejected by PathLen: %d (%.1f%%), with excluded keys: %d \n", s.Words, s.Rejected, s.RejectedPart()*100, s.Excluded)
+	fmt.Printf("mean path length: %.2f \n\n", s.MeanPathLen)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "LENGTH\tWORDS\tCHEAPEST")
+	for _, l := range sortedKeys(s.ByLength) {
+		words := make([]string, 0, len(s.Cheapest[l]))
+		for _, wc := range s.Cheapest[l] {
+			words = append(words, fmt.Sprintf("%s (%d)", wc.Word, wc.PathLen))
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%s\n", l, s.ByLength[l], strings.Join(words, ", "))
+	}
+
+	_, _ = fmt.Fprintln(w, "\nPATH LENGTH\tWORDS\t")
+	for _, n := range sortedKeys(s.PathLens) {
+		_, _ = fmt.Fprintf(w, "%d\t%d\t\n", n, s.PathLens[n])
+	}
+
+	_, _ = fmt.Fprintln(w, "\nLETTER\tFIRST\tLAST")
+	for c := 'a'; c <= 'z'; c++ {
+		if first, last := s.First[string(c)], s.Last[string(c)]; first > 0 || last > 0 {
+			_, _ = fmt.Fprintf(w, "%c\t%d\t%d\n", c, first, last)
+		}
+	}
+	_ = w.Flush()
+}
+
+func sortedKeys(m map[int]int) []int {
+	res := make([]int, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Ints(res)
+	return res
+}
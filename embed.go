@@ -0,0 +1,81 @@
+// Package grannypass bundles data files into the binary: keyboard layouts, precomputed distance maps and vocabularies
+package grannypass
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// large word lists, e.g. words_alpha.txt, are not bundled, they are read from the working directory
+//
+//go:embed layouts/*.json distanceMaps/*.json
+//go:embed vocabularies/short.txt vocabularies/10000.txt vocabularies/20000.txt vocabularies/40000.txt
+var Data embed.FS
+
+// FS returns files of dir on disk, files missing on disk are taken from the bundled Data
+func FS(dir string) fs.FS {
+	return Overlay(os.DirFS(dir), Data)
+}
+
+type overlay []fs.FS
+
+// Overlay returns file system which looks for a file in layers in order, the first found file is used
+func Overlay(layers ...fs.FS) fs.FS {
+	return overlay(layers)
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, layer := range o {
+		f, err := layer.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir merges entries of the directory from all layers, entries of upper layers win
+func (o overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	var (
+		res   []fs.DirEntry
+		found bool
+	)
+
+	names := make(map[string]bool)
+	for _, layer := range o {
+		entries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		found = true
+		for _, e := range entries {
+			if !names[e.Name()] {
+				names[e.Name()] = true
+				res = append(res, e)
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+	return res, nil
+}
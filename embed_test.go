@@ -0,0 +1,87 @@
+//go:build embedTest
+// +build embedTest
+
+package grannypass
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbed(t *testing.T) {
+	t.Run("test bundled data", func(t *testing.T) {
+		var (
+			data []byte
+			err  error
+		)
+
+		t.Run("Data", func(t *testing.T) {
+			for _, name := range []string{
+				"layouts/qwerty.json",
+				"vocabularies/short.txt",
+			} {
+				_, err = fs.Stat(Data, name)
+				assert.NoError(t, err, name)
+			}
+
+			_, err = fs.Stat(Data, "vocabularies/words_alpha.txt")
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+
+			//distance maps are named by the layout hash
+			for _, pattern := range []string{
+				"distanceMaps/dm_qwerty_task_hops_*.json",
+				"distanceMaps/dm_qwerty_normalized_hops_*.json",
+			} {
+				names, err := fs.Glob(Data, pattern)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(names), pattern)
+			}
+		})
+
+		t.Run("Overlay", func(t *testing.T) {
+			upper := fstest.MapFS{
+				"vocabularies/short.txt": {Data: []byte("upper")},
+				"vocabularies/my.txt":    {Data: []byte("my")},
+			}
+			lower := fstest.MapFS{
+				"vocabularies/short.txt": {Data: []byte("lower")},
+				"vocabularies/long.txt":  {Data: []byte("long")},
+			}
+			o := Overlay(upper, lower)
+
+			data, err = fs.ReadFile(o, "vocabularies/short.txt")
+			assert.NoError(t, err)
+			assert.Equal(t, "upper", string(data))
+
+			data, err = fs.ReadFile(o, "vocabularies/long.txt")
+			assert.NoError(t, err)
+			assert.Equal(t, "long", string(data))
+
+			_, err = fs.ReadFile(o, "vocabularies/none.txt")
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+
+			_, err = o.Open("../short.txt")
+			assert.ErrorIs(t, err, fs.ErrInvalid)
+
+			entries, err := fs.ReadDir(o, "vocabularies")
+			assert.NoError(t, err)
+			names := make([]string, 0, len(entries))
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			assert.Equal(t, []string{"long.txt", "my.txt", "short.txt"}, names)
+
+			_, err = fs.ReadDir(o, "none")
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+		})
+
+		t.Run("FS", func(t *testing.T) {
+			data, err = fs.ReadFile(FS(t.TempDir()), "layouts/qwerty.json")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, data)
+		})
+	})
+}
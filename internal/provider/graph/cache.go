@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CacheVersion is the version of the cache format and of distance calculation.
+// Increase it when the format or the graph algorithms change, old caches become stale
+const CacheVersion = 2
+
+var ErrStaleCache = errors.New("distance map cache is stale")
+
+// CacheMeta describes what distance map was calculated from, the cache is valid only for the same meta
+type CacheMeta struct {
+	Version    int    `json:"version"`
+	Layout     string `json:"layout"`
+	Mode       string `json:"mode"`
+	Model      string `json:"model"`
+	LayoutHash string `json:"layoutHash"`
+}
+
+type cacheFile struct {
+	Meta      CacheMeta `json:"meta"`
+	Distances []int     `json:"distances"`
+}
+
+// NewCacheMeta returns meta of the current CacheVersion
+func NewCacheMeta(layout, mode, model, layoutHash string) CacheMeta {
+	return CacheMeta{
+		Version:    CacheVersion,
+		Layout:     layout,
+		Mode:       mode,
+		Model:      model,
+		LayoutHash: layoutHash,
+	}
+}
+
+// SaveCache writes distance map with its meta. The directory is created if needed,
+// the file is replaced atomically, so readers never see a partially written cache
+func SaveCache(m []int, meta CacheMeta, filename string) error {
+	data, err := json.Marshal(cacheFile{Meta: meta, Distances: m})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filename, data)
+}
+
+// writeFileAtomic creates the directory if needed and replaces the file with data through a temporary file
+func writeFileAtomic(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+// ReadCache reads distance map saved by SaveCache. It returns ErrStaleCache
+// if the file was written in other format or its meta differs from meta
+func ReadCache(filename string, meta CacheMeta) ([]int, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCache(data, filename, meta)
+}
+
+// ReadCacheFS reads distance map from the file system, e.g. embedded into the binary, see ReadCache
+func ReadCacheFS(fsys fs.FS, filename string, meta CacheMeta) ([]int, error) {
+	data, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCache(data, filename, meta)
+}
+
+func parseCache(data []byte, filename string, meta CacheMeta) ([]int, error) {
+	var c cacheFile
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrStaleCache, filename, err)
+	}
+
+	if c.Meta != meta {
+		return nil, fmt.Errorf("%w: %s: %+v, expected %+v", ErrStaleCache, filename, c.Meta, meta)
+	}
+
+	if len(c.Distances) != 32*32 {
+		return nil, fmt.Errorf("%w: %s: %d distances", ErrStaleCache, filename, len(c.Distances))
+	}
+	return c.Distances, nil
+}
@@ -0,0 +1,72 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("test cache functions", func(t *testing.T) {
+		var (
+			dist     = BigramDistanceArray(getDistMapForTest())
+			meta     = NewCacheMeta("small", "task", "hops maxPathLen=20", "abc")
+			filename = filepath.Join(t.TempDir(), "new", "dm.json")
+			m        []int
+			err      error
+		)
+
+		t.Run("SaveCache creates directory", func(t *testing.T) {
+			err = SaveCache(dist, meta, filename)
+			assert.Nil(t, err)
+
+			entries, err := os.ReadDir(filepath.Dir(filename))
+			assert.Nil(t, err)
+			//no temporary files left
+			assert.Equal(t, 1, len(entries))
+		})
+
+		t.Run("ReadCache", func(t *testing.T) {
+			m, err = ReadCache(filename, meta)
+			assert.Nil(t, err)
+			assert.Equal(t, dist, m)
+		})
+
+		t.Run("ReadCacheFS", func(t *testing.T) {
+			m, err = ReadCacheFS(os.DirFS(filepath.Dir(filename)), filepath.Base(filename), meta)
+			assert.Nil(t, err)
+			assert.Equal(t, dist, m)
+		})
+
+		t.Run("ReadCache with other meta", func(t *testing.T) {
+			other := meta
+			other.LayoutHash = "abd"
+			_, err = ReadCache(filename, other)
+			assert.ErrorIs(t, err, ErrStaleCache)
+
+			other = meta
+			other.Version = CacheVersion + 1
+			_, err = ReadCache(filename, other)
+			assert.ErrorIs(t, err, ErrStaleCache)
+		})
+
+		t.Run("ReadCache of old format", func(t *testing.T) {
+			old := filepath.Join(t.TempDir(), "dm.json")
+			err = SaveToJson(dist, old)
+			assert.Nil(t, err)
+
+			_, err = ReadCache(old, meta)
+			assert.ErrorIs(t, err, ErrStaleCache)
+		})
+
+		t.Run("ReadCache of missing file", func(t *testing.T) {
+			_, err = ReadCache(filepath.Join(t.TempDir(), "dm.json"), meta)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package graph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotSquare        = errors.New("matrix is not square")
+	ErrNegativeDistance = errors.New("negative distance")
+	ErrNonZeroDiagonal  = errors.New("non-zero distance from the key to itself")
+	ErrKeyNotCovered    = errors.New("key is not covered by the matrix")
+)
+
+// SaveToCsv writes distances with keys as labels of rows and columns.
+// Values are separated by tab for .tsv file and by comma otherwise
+func SaveToCsv(m map[string]map[string]int, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = WriteCsv(file, m, separator(filename))
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// ReadFromCsv reads distances written by SaveToCsv or edited by hand, see ReadCsv
+func ReadFromCsv(filename string) (map[string]map[string]int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	m, err := ReadCsv(file, separator(filename))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return m, nil
+}
+
+// WriteCsv writes the header with column keys and then one row per key, letters go first
+func WriteCsv(w io.Writer, m map[string]map[string]int, comma rune) error {
+	keys := sortedKeys(m)
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	if err := writer.Write(append([]string{""}, keys...)); err != nil {
+		return err
+	}
+
+	for _, k1 := range keys {
+		record := make([]string, 0, len(keys)+1)
+		record = append(record, k1)
+		for _, k2 := range keys {
+			record = append(record, strconv.Itoa(m[k1][k2]))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCsv reads labelled matrix and checks that it is square with the same keys in rows and columns,
+// distances are non-negative integers and distance from every key to itself is zero
+func ReadCsv(r io.Reader, comma rune) (map[string]map[string]int, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, ErrNoVertices
+	}
+
+	header := records[0][1:]
+	if len(records)-1 != len(header) {
+		return nil, fmt.Errorf("%w: %d columns and %d rows", ErrNotSquare, len(header), len(records)-1)
+	}
+
+	columns := make(map[string]bool, len(header))
+	for _, k := range header {
+		k = strings.TrimSpace(k)
+		if k == "" || columns[k] {
+			return nil, fmt.Errorf("wrong or duplicate column key %q", k)
+		}
+		columns[k] = true
+	}
+
+	m := make(map[string]map[string]int, len(header))
+	for n, record := range records[1:] {
+		k1 := strings.TrimSpace(record[0])
+		if !columns[k1] {
+			return nil, fmt.Errorf("%w: row key %q is not in columns", ErrNotSquare, k1)
+		}
+		if _, ok := m[k1]; ok {
+			return nil, fmt.Errorf("duplicate row key %q", k1)
+		}
+
+		m[k1] = make(map[string]int, len(header))
+		for i, value := range record[1:] {
+			k2 := strings.TrimSpace(header[i])
+
+			d, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column %q: %w", n+2, k2, err)
+			}
+			if d < 0 {
+				return nil, fmt.Errorf("%w: %s -> %s", ErrNegativeDistance, k1, k2)
+			}
+			if k1 == k2 && d != 0 {
+				return nil, fmt.Errorf("%w: %s", ErrNonZeroDiagonal, k1)
+			}
+
+			m[k1][k2] = d
+		}
+	}
+	return m, nil
+}
+
+// CheckCoverage checks that distances between all letters are in m
+func CheckCoverage(m map[string]map[string]int, letters string) error {
+	for i := 0; i < len(letters); i++ {
+		row, ok := m[letters[i:i+1]]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrKeyNotCovered, letters[i])
+		}
+		for j := 0; j < len(letters); j++ {
+			if _, ok = row[letters[j:j+1]]; !ok {
+				return fmt.Errorf("%w: %q -> %q", ErrKeyNotCovered, letters[i], letters[j])
+			}
+		}
+	}
+	return nil
+}
+
+func separator(filename string) rune {
+	if strings.ToLower(filepath.Ext(filename)) == ".tsv" {
+		return '\t'
+	}
+	return ','
+}
+
+// sortedKeys returns letters in alphabetical order and then other keys
+func sortedKeys(m map[string]map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		li, lj := isLetter(keys[i]), isLetter(keys[j])
+		if li != lj {
+			return li
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
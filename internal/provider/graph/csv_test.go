@@ -0,0 +1,94 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsv(t *testing.T) {
+	t.Run("test csv functions", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			m   map[string]map[string]int
+			err error
+		)
+
+		t.Run("WriteCsv", func(t *testing.T) {
+			mk := getDistMapForTest()
+			mk["enter"] = map[string]int{"a": 3, "s": 2, "d": 1, "enter": 0}
+			for _, k := range []string{"a", "s", "d"} {
+				mk[k]["enter"] = mk["enter"][k]
+			}
+
+			err = WriteCsv(&buf, mk, ',')
+			assert.Nil(t, err)
+			assert.Equal(t, ",a,d,s,enter\na,0,2,1,3\nd,2,0,1,1\ns,1,1,0,2\nenter,3,1,2,0\n", buf.String())
+		})
+
+		t.Run("ReadCsv", func(t *testing.T) {
+			m, err = ReadCsv(&buf, ',')
+			assert.Nil(t, err)
+			assert.Equal(t, 4, len(m))
+			assert.Equal(t, 2, m["a"]["d"])
+			assert.Equal(t, 3, m["enter"]["a"])
+		})
+
+		t.Run("ReadCsv with tab and rows in other order", func(t *testing.T) {
+			m, err = ReadCsv(strings.NewReader("\ta\tb\nb\t4\t0\na\t0\t5\n"), '\t')
+			assert.Nil(t, err)
+			assert.Equal(t, 5, m["a"]["b"])
+			assert.Equal(t, 4, m["b"]["a"])
+		})
+
+		t.Run("ReadCsv validates matrix", func(t *testing.T) {
+			_, err = ReadCsv(strings.NewReader(",a,b\na,0,1\n"), ',')
+			assert.ErrorIs(t, err, ErrNotSquare)
+
+			_, err = ReadCsv(strings.NewReader(",a,b\na,0,1\nc,1,0\n"), ',')
+			assert.ErrorIs(t, err, ErrNotSquare)
+
+			_, err = ReadCsv(strings.NewReader(",a,b\na,0,-1\nb,1,0\n"), ',')
+			assert.ErrorIs(t, err, ErrNegativeDistance)
+
+			_, err = ReadCsv(strings.NewReader(",a,b\na,1,1\nb,1,0\n"), ',')
+			assert.ErrorIs(t, err, ErrNonZeroDiagonal)
+
+			_, err = ReadCsv(strings.NewReader(",a,b\na,0,x\nb,1,0\n"), ',')
+			assert.NotNil(t, err)
+
+			_, err = ReadCsv(strings.NewReader(""), ',')
+			assert.ErrorIs(t, err, ErrNoVertices)
+		})
+
+		t.Run("CheckCoverage", func(t *testing.T) {
+			m = getDistMapForTest()
+			assert.Nil(t, CheckCoverage(m, "ads"))
+			assert.Nil(t, CheckCoverage(m, ""))
+			assert.ErrorIs(t, CheckCoverage(m, "adz"), ErrKeyNotCovered)
+
+			delete(m["a"], "s")
+			assert.ErrorIs(t, CheckCoverage(m, "as"), ErrKeyNotCovered)
+		})
+
+		t.Run("SaveToCsv & ReadFromCsv", func(t *testing.T) {
+			m = getDistMapForTest()
+			for _, name := range []string{"dm.csv", "dm.tsv"} {
+				filename := filepath.Join(t.TempDir(), name)
+
+				err = SaveToCsv(m, filename)
+				assert.Nil(t, err)
+
+				mr, err := ReadFromCsv(filename)
+				assert.Nil(t, err)
+				assert.Equal(t, m, mr)
+			}
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// directed graph keeps every direction as a separate edge with its own weight,
+// e.g. moving a finger up-right may be easier than down-left
+type directed[K comparable, V Vertex] struct {
+	hash    Hash[K, V]
+	storage Storage[K, V]
+}
+
+func newDirected[K comparable, V Vertex](hash Hash[K, V], storage Storage[K, V]) *directed[K, V] {
+	return &directed[K, V]{
+		hash:    hash,
+		storage: storage,
+	}
+}
+
+func (d *directed[K, V]) AddVertex(value V, options ...func(*VertexProperties)) error {
+	return addVertex(d.storage, d.hash(value), value, options)
+}
+
+func (d *directed[K, V]) VertexProperties(hash K) (VertexProperties, error) {
+	return d.storage.VertexProperties(hash)
+}
+
+func (d *directed[K, V]) SetVertexProperties(hash K, properties VertexProperties) error {
+	return d.storage.SetVertexProperties(hash, properties)
+}
+
+func (d *directed[K, V]) Vertex(hash K) (V, error) {
+	return d.storage.Vertex(hash)
+}
+
+func (d *directed[K, V]) AddEdge(source, target K) error {
+	return d.AddWeightedEdge(source, target, 1)
+}
+
+// AddWeightedEdge adds edge source -> target only, the reverse direction should be added separately
+func (d *directed[K, V]) AddWeightedEdge(source, target K, weight int) error {
+	if weight < 0 {
+		return ErrNegativeWeight
+	}
+
+	if _, err := d.storage.Vertex(source); err != nil {
+		return fmt.Errorf("could not find source vertex with hash %v: %w", source, err)
+	}
+
+	if _, err := d.storage.Vertex(target); err != nil {
+		return fmt.Errorf("could not find target vertex with hash %v: %w", target, err)
+	}
+
+	if _, err := d.storage.Edge(source, target); !errors.Is(err, ErrEdgeNotFound) {
+		return ErrEdgeAlreadyExists
+	}
+
+	edge := Edge[K]{
+		Source: source,
+		Target: target,
+		Weight: weight,
+	}
+
+	if err := d.storage.AddEdge(source, target, edge); err != nil {
+		return fmt.Errorf("failed to add edge: %w", err)
+	}
+
+	return nil
+}
+
+func (d *directed[K, V]) Edge(source, target K) (Edge[V], error) {
+	edge, err := d.storage.Edge(source, target)
+	if err != nil {
+		return Edge[V]{}, err
+	}
+
+	sourceVertex, err := d.storage.Vertex(source)
+	if err != nil {
+		return Edge[V]{}, err
+	}
+
+	targetVertex, err := d.storage.Vertex(target)
+	if err != nil {
+		return Edge[V]{}, err
+	}
+
+	return Edge[V]{
+		Source: sourceVertex,
+		Target: targetVertex,
+		Weight: edge.Weight,
+	}, nil
+}
+
+// RemoveEdge removes the edge source -> target only
+func (d *directed[K, V]) RemoveEdge(source, target K) error {
+	return d.storage.RemoveEdge(source, target)
+}
+
+func (d *directed[K, V]) RemoveVertex(hash K) error {
+	return d.storage.RemoveVertex(hash)
+}
+
+func (d *directed[K, V]) Neighbors(hash K) ([]K, error) {
+	return neighbors(d.storage, hash)
+}
+
+func (d *directed[K, V]) Edges() ([]Edge[K], error) {
+	edges, err := d.storage.ListEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	sortEdges(edges)
+	return edges, nil
+}
+
+func (d *directed[K, V]) Size() (int, error) {
+	return d.storage.EdgeCount()
+}
+
+func (d *directed[K, V]) Vertices() ([]K, error) {
+	return vertices(d.storage)
+}
+
+func (d *directed[K, V]) IsDirected() bool {
+	return true
+}
+
+func (d *directed[K, V]) Order() (int, error) {
+	return d.storage.VertexCount()
+}
+
+func (d *directed[K, V]) VertexWeights() (map[K]int, error) {
+	return vertexWeights(d.storage)
+}
+
+func (d *directed[K, V]) AdjacencyMapWithMaxWeight(maxN int) (map[K]map[K]int, error) {
+	return adjacencyMapWithMaxWeight(d.storage, maxN)
+}
+
+func (d *directed[K, V]) WFI(maxN int) (map[K]map[K]int, error) {
+	return wfi(d.storage, maxN)
+}
+
+func (d *directed[K, V]) BFS(maxN int) (map[K]map[K]int, error) {
+	return bfs(d.storage, maxN)
+}
+
+func (d *directed[K, V]) Dijkstra(maxN int) (map[K]map[K]int, error) {
+	return dijkstra(d.storage, maxN)
+}
+
+func (d *directed[K, V]) ShortestPaths(maxN int) (map[K]map[K]int, error) {
+	return shortestPaths(d.storage, maxN)
+}
+
+func (d *directed[K, V]) Components() ([][]K, error) {
+	return components(d.storage)
+}
+
+func (d *directed[K, V]) Validate(maxN int) (map[K]map[K]int, error) {
+	return validate(d.storage, maxN)
+}
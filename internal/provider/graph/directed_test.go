@@ -0,0 +1,188 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirected(t *testing.T) {
+
+	t.Run("test directed graph functions", func(t *testing.T) {
+		var (
+			v1, v2, v3 = Vertex{Name: "a"}, Vertex{Name: "s"}, Vertex{Name: "d"}
+			r1         Vertex
+			rEdge      Edge[Vertex]
+			err        error
+			order      int
+			m          map[string]map[string]int
+			maxN       = 20
+		)
+
+		hash := func(v Vertex) string {
+			return v.Name
+		}
+
+		g := newDirected(hash, newMemoryStorage[string]())
+
+		t.Run("empty graph", func(t *testing.T) {
+			m, err = g.WFI(maxN)
+			assert.Error(t, err)
+		})
+
+		t.Run("AddVertex", func(t *testing.T) {
+			err = g.AddVertex(v1)
+			assert.NoError(t, err)
+			err = g.AddVertex(v2)
+			assert.NoError(t, err)
+			err = g.AddVertex(v3)
+			assert.NoError(t, err)
+
+			err = g.AddVertex(v3)
+			assert.Error(t, err)
+
+			order, err = g.Order()
+			assert.NoError(t, err)
+			assert.Equal(t, 3, order)
+
+			r1, err = g.Vertex(hash(v1))
+			assert.NoError(t, err)
+			assert.Equal(t, v1, r1)
+		})
+
+		t.Run("AddEdge", func(t *testing.T) {
+			err = g.AddEdge(hash(v1), hash(v2))
+			assert.NoError(t, err)
+
+			t.Run("check direct", func(t *testing.T) {
+				rEdge, err = g.Edge(hash(v1), hash(v2))
+				assert.NoError(t, err)
+				assert.Equal(t, v1, rEdge.Source)
+				assert.Equal(t, v2, rEdge.Target)
+				assert.Equal(t, 1, rEdge.Weight)
+			})
+
+			t.Run("check reverse", func(t *testing.T) {
+				rEdge, err = g.Edge(hash(v2), hash(v1))
+				assert.ErrorIs(t, err, ErrEdgeNotFound)
+			})
+
+			t.Run("add existent", func(t *testing.T) {
+				err = g.AddEdge(hash(v1), hash(v2))
+				assert.ErrorIs(t, err, ErrEdgeAlreadyExists)
+			})
+
+			t.Run("add nonexistent vertex", func(t *testing.T) {
+				err = g.AddEdge(hash(v1), "x")
+				assert.ErrorIs(t, err, ErrVertexNotFound)
+				err = g.AddEdge("x", hash(v1))
+				assert.ErrorIs(t, err, ErrVertexNotFound)
+			})
+		})
+
+		t.Run("AddWeightedEdge", func(t *testing.T) {
+			err = g.AddWeightedEdge(hash(v2), hash(v1), 3)
+			assert.NoError(t, err)
+
+			rEdge, err = g.Edge(hash(v2), hash(v1))
+			assert.NoError(t, err)
+			assert.Equal(t, 3, rEdge.Weight)
+
+			err = g.AddWeightedEdge(hash(v2), hash(v3), 2)
+			assert.NoError(t, err)
+			err = g.AddWeightedEdge(hash(v3), hash(v2), 1)
+			assert.NoError(t, err)
+
+			err = g.AddWeightedEdge(hash(v1), hash(v3), -1)
+			assert.ErrorIs(t, err, ErrNegativeWeight)
+		})
+
+		t.Run("AdjacencyMapWithMaxWeight", func(t *testing.T) {
+			m, err = g.AdjacencyMapWithMaxWeight(maxN)
+			assert.NoError(t, err)
+
+			assert.Equal(t, 0, m[hash(v1)][hash(v1)])
+			assert.Equal(t, 1, m[hash(v1)][hash(v2)])
+			assert.Equal(t, 3, m[hash(v2)][hash(v1)])
+			assert.Equal(t, 2, m[hash(v2)][hash(v3)])
+			assert.Equal(t, 1, m[hash(v3)][hash(v2)])
+			assert.Equal(t, maxN, m[hash(v1)][hash(v3)])
+			assert.Equal(t, maxN, m[hash(v3)][hash(v1)])
+		})
+
+		t.Run("WFI is asymmetric", func(t *testing.T) {
+			m, err = g.WFI(maxN)
+			assert.NoError(t, err)
+
+			assert.Equal(t, 0, m[hash(v2)][hash(v2)])
+			assert.Equal(t, 1, m[hash(v1)][hash(v2)])
+			assert.Equal(t, 3, m[hash(v2)][hash(v1)])
+			assert.Equal(t, 3, m[hash(v1)][hash(v3)])
+			assert.Equal(t, 4, m[hash(v3)][hash(v1)])
+
+			dist := BigramDistanceArray(m)
+			assert.Equal(t, 3, dist[getIndex('a', 'd')])
+			assert.Equal(t, 4, dist[getIndex('d', 'a')])
+		})
+	})
+
+	t.Run("test removing and listing for directed graph", func(t *testing.T) {
+		var (
+			edges []Edge[string]
+			keys  []string
+			size  int
+			err   error
+		)
+
+		g := NewDirected(func(v Vertex) string {
+			return v.Name
+		})
+		for _, name := range []string{"a", "b"} {
+			_ = g.AddVertex(Vertex{Name: name})
+		}
+		_ = g.AddWeightedEdge("a", "b", 1)
+		_ = g.AddWeightedEdge("b", "a", 3)
+
+		t.Run("Edges & Size", func(t *testing.T) {
+			edges, err = g.Edges()
+			assert.NoError(t, err)
+			assert.Equal(t, []Edge[string]{{Source: "a", Target: "b", Weight: 1}, {Source: "b", Target: "a", Weight: 3}}, edges)
+
+			size, err = g.Size()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, size)
+		})
+
+		t.Run("RemoveEdge removes one direction", func(t *testing.T) {
+			err = g.RemoveEdge("b", "a")
+			assert.NoError(t, err)
+
+			keys, err = g.Neighbors("b")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{}, keys)
+
+			keys, err = g.Neighbors("a")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"b"}, keys)
+
+			size, err = g.Size()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, size)
+		})
+
+		t.Run("RemoveVertex", func(t *testing.T) {
+			// ingoing edge a -> b is left
+			err = g.RemoveVertex("b")
+			assert.ErrorIs(t, err, ErrVertexHasEdges)
+
+			err = g.RemoveEdge("a", "b")
+			assert.NoError(t, err)
+
+			err = g.RemoveVertex("b")
+			assert.NoError(t, err)
+		})
+	})
+}
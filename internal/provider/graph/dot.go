@@ -0,0 +1,542 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrDotSyntax = errors.New("dot syntax error")
+
+// attributes of vertices written to DOT besides arbitrary ones
+const (
+	dotPos    = "pos"
+	dotWidth  = "width"
+	dotRow    = "row"
+	dotFinger = "finger"
+	dotHand   = "hand"
+	dotPress  = "press"
+	dotLabel  = "label"
+	dotWeight = "weight"
+)
+
+// WriteDot writes the graph in Graphviz DOT format. Positions of vertices are taken from their coordinates
+// in key units as inches, y goes up in Graphviz, so it is negated. Weights of edges are written as labels.
+// Use neato to keep positions: neato -Tsvg keyboard.dot
+func WriteDot[K comparable, V Vertex](w io.Writer, g Graph[K, V], name string) error {
+	keyword, op := "graph", "--"
+	if g.IsDirected() {
+		keyword, op = "digraph", "->"
+	}
+
+	vertices, err := g.Vertices()
+	if err != nil {
+		return err
+	}
+
+	edges, err := g.Edges()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "%s %s {\n", keyword, dotID(name))
+	_, _ = fmt.Fprintln(bw, "\tnode [shape=box];")
+
+	for _, hash := range vertices {
+		v, err := g.Vertex(hash)
+		if err != nil {
+			return err
+		}
+
+		p, err := g.VertexProperties(hash)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(bw, "\t%s%s;\n", dotID(fmt.Sprint(hash)), dotAttributes(vertexAttributes(Vertex(v), p)))
+	}
+
+	for _, e := range edges {
+		_, _ = fmt.Fprintf(bw, "\t%s %s %s [%s=%d];\n", dotID(fmt.Sprint(e.Source)), op, dotID(fmt.Sprint(e.Target)), dotLabel, e.Weight)
+	}
+
+	_, _ = fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// ReadDot reads graph written by WriteDot or by hand, vertices are hashed by names. It supports node and edge
+// statements with attributes, chains of edges a -- b -- c, default attributes and comments, but not subgraphs.
+// Weight of the edge is taken from its label or weight attribute, 1 by default
+func ReadDot(r io.Reader) (Graph[string, Vertex], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := dotTokens(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{tokens: tokens, nodes: make(map[string]map[string]string)}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+
+	return p.graph()
+}
+
+func vertexAttributes(v Vertex, p VertexProperties) [][2]string {
+	var res [][2]string
+
+	if p.X != 0 || p.Y != 0 {
+		res = append(res, [2]string{dotPos, formatFloat(p.X) + "," + formatFloat(-p.Y) + "!"})
+	}
+	if p.Width != 0 {
+		res = append(res, [2]string{dotWidth, formatFloat(p.Width)})
+	}
+	if p.Row != 0 {
+		res = append(res, [2]string{dotRow, strconv.Itoa(p.Row)})
+	}
+	if p.Finger != "" {
+		res = append(res, [2]string{dotFinger, p.Finger})
+	}
+	if p.Hand != "" {
+		res = append(res, [2]string{dotHand, p.Hand})
+	}
+	if v.Weight != 0 {
+		res = append(res, [2]string{dotPress, strconv.Itoa(v.Weight)})
+	}
+
+	keys := make([]string, 0, len(p.Attributes))
+	for k := range p.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		res = append(res, [2]string{k, p.Attributes[k]})
+	}
+	return res
+}
+
+func dotAttributes(attrs [][2]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	s := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		s = append(s, dotID(a[0])+"="+dotID(a[1]))
+	}
+	return " [" + strings.Join(s, ", ") + "]"
+}
+
+// dotID returns the identifier as is if it is a word or a number, otherwise quoted
+func dotID(s string) string {
+	if s != "" && isDotWord(s) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "eE+") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func isDotWord(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return !isDotKeyword(s)
+}
+
+func isDotKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "graph", "digraph", "node", "edge", "strict", "subgraph":
+		return true
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type dotToken struct {
+	value string
+	// quoted identifier is never a keyword or punctuation
+	quoted bool
+	line   int
+}
+
+func (t dotToken) is(s string) bool {
+	return !t.quoted && strings.EqualFold(t.value, s)
+}
+
+func dotTokens(s string) ([]dotToken, error) {
+	var res []dotToken
+
+	line := 1
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' && (i == 0 || s[i-1] == '\n'):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "//"):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: line %d: unclosed comment", ErrDotSyntax, line)
+			}
+			line += strings.Count(s[i:i+2+end], "\n")
+			i += end + 4
+		case strings.HasPrefix(s[i:], "--") || strings.HasPrefix(s[i:], "->"):
+			res = append(res, dotToken{value: s[i : i+2], line: line})
+			i += 2
+		case strings.ContainsRune("{}[]=;,", rune(c)):
+			res = append(res, dotToken{value: string(c), line: line})
+			i++
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) && (s[j+1] == '"' || s[j+1] == '\\') {
+					j++
+				}
+				if s[j] == '\n' {
+					line++
+				}
+				b.WriteByte(s[j])
+			}
+			if j == len(s) {
+				return nil, fmt.Errorf("%w: line %d: unclosed string", ErrDotSyntax, line)
+			}
+			res = append(res, dotToken{value: b.String(), quoted: true, line: line})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && isDotIDByte(s[j]) && !strings.HasPrefix(s[j:], "--") && !strings.HasPrefix(s[j:], "->") {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("%w: line %d: unexpected %q", ErrDotSyntax, line, c)
+			}
+			res = append(res, dotToken{value: s[i:j], line: line})
+			i = j
+		}
+	}
+	return res, nil
+}
+
+func isDotIDByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+type dotEdge struct {
+	source, target string
+	attrs          map[string]string
+}
+
+type dotParser struct {
+	tokens   []dotToken
+	pos      int
+	directed bool
+	// nodes in order of appearance
+	names []string
+	nodes map[string]map[string]string
+	edges []dotEdge
+}
+
+func (p *dotParser) peek() (dotToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return dotToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *dotParser) next() (dotToken, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, fmt.Errorf("%w: unexpected end", ErrDotSyntax)
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *dotParser) expect(s string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if !t.is(s) {
+		return fmt.Errorf("%w: line %d: expected %q, got %q", ErrDotSyntax, t.line, s, t.value)
+	}
+	return nil
+}
+
+func (p *dotParser) parse() error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.is("strict") {
+		if t, err = p.next(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case t.is("graph"):
+	case t.is("digraph"):
+		p.directed = true
+	default:
+		return fmt.Errorf("%w: line %d: expected graph or digraph, got %q", ErrDotSyntax, t.line, t.value)
+	}
+
+	//name of the graph
+	if t, ok := p.peek(); ok && !t.is("{") {
+		p.pos++
+	}
+
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+
+	for {
+		t, err = p.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case t.is("}"):
+			if t, ok := p.peek(); ok {
+				return fmt.Errorf("%w: line %d: unexpected %q after the graph", ErrDotSyntax, t.line, t.value)
+			}
+			return nil
+		case t.is(";"):
+		case t.is("subgraph") || t.is("{"):
+			return fmt.Errorf("%w: line %d: subgraphs are not supported", ErrDotSyntax, t.line)
+		case t.is("graph") || t.is("node") || t.is("edge"):
+			//default attributes do not describe the keyboard
+			if _, err = p.attributes(); err != nil {
+				return err
+			}
+		case !t.quoted && strings.ContainsAny(t.value, "[]=,") || t.is("--") || t.is("->"):
+			return fmt.Errorf("%w: line %d: unexpected %q", ErrDotSyntax, t.line, t.value)
+		default:
+			if err = p.statement(t); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// statement parses node, edge or graph attribute statement starting with id
+func (p *dotParser) statement(id dotToken) error {
+	if t, ok := p.peek(); ok && t.is("=") {
+		//graph attribute
+		p.pos++
+		_, err := p.id()
+		return err
+	}
+
+	ids := []string{id.value}
+	for {
+		t, ok := p.peek()
+		if !ok || !(t.is("--") || t.is("->")) {
+			break
+		}
+		if t.is("--") == p.directed {
+			return fmt.Errorf("%w: line %d: edge %q does not match the graph type", ErrDotSyntax, t.line, t.value)
+		}
+		p.pos++
+
+		next, err := p.id()
+		if err != nil {
+			return err
+		}
+		ids = append(ids, next)
+	}
+
+	attrs, err := p.attributes()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range ids {
+		p.node(name)
+	}
+
+	if len(ids) == 1 {
+		for k, v := range attrs {
+			p.nodes[id.value][k] = v
+		}
+		return nil
+	}
+
+	for i := 1; i < len(ids); i++ {
+		p.edges = append(p.edges, dotEdge{source: ids[i-1], target: ids[i], attrs: attrs})
+	}
+	return nil
+}
+
+func (p *dotParser) id() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if !t.quoted && (strings.ContainsAny(t.value, "{}[]=;,") || t.is("--") || t.is("->") || isDotKeyword(t.value)) {
+		return "", fmt.Errorf("%w: line %d: expected identifier, got %q", ErrDotSyntax, t.line, t.value)
+	}
+	return t.value, nil
+}
+
+// attributes parses lists [a=b, c=d][e=f], they may be absent
+func (p *dotParser) attributes() (map[string]string, error) {
+	res := make(map[string]string)
+
+	for {
+		t, ok := p.peek()
+		if !ok || !t.is("[") {
+			return res, nil
+		}
+		p.pos++
+
+		for {
+			t, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if t.is("]") {
+				break
+			}
+			if t.is(",") || t.is(";") {
+				continue
+			}
+
+			if err = p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			res[t.value] = value
+		}
+	}
+}
+
+func (p *dotParser) node(name string) {
+	if _, ok := p.nodes[name]; !ok {
+		p.names = append(p.names, name)
+		p.nodes[name] = make(map[string]string)
+	}
+}
+
+func (p *dotParser) graph() (Graph[string, Vertex], error) {
+	hash := func(v Vertex) string {
+		return v.Name
+	}
+
+	g := New(hash)
+	if p.directed {
+		g = NewDirected(hash)
+	}
+
+	for _, name := range p.names {
+		v, props, err := dotVertex(name, p.nodes[name])
+		if err != nil {
+			return nil, err
+		}
+		if err = g.AddVertex(v, WithVertexProperties(props)); err != nil {
+			return nil, fmt.Errorf("vertex %q: %w", name, err)
+		}
+	}
+
+	for _, e := range p.edges {
+		weight := 1
+		for _, k := range []string{dotLabel, dotWeight} {
+			if s, ok := e.attrs[k]; ok {
+				w, err := strconv.Atoi(s)
+				if err != nil {
+					return nil, fmt.Errorf("edge %s - %s: %s %q: %w", e.source, e.target, k, s, err)
+				}
+				weight = w
+				break
+			}
+		}
+
+		if err := g.AddWeightedEdge(e.source, e.target, weight); err != nil {
+			return nil, fmt.Errorf("edge %s - %s: %w", e.source, e.target, err)
+		}
+	}
+	return g, nil
+}
+
+func dotVertex(name string, attrs map[string]string) (Vertex, VertexProperties, error) {
+	var (
+		v   = Vertex{Name: name}
+		p   VertexProperties
+		err error
+	)
+
+	for k, value := range attrs {
+		switch k {
+		case dotPos:
+			x, y, ok := strings.Cut(strings.TrimSuffix(value, "!"), ",")
+			if !ok {
+				return v, p, fmt.Errorf("vertex %q: %w: pos %q", name, ErrDotSyntax, value)
+			}
+			if p.X, err = strconv.ParseFloat(x, 64); err != nil {
+				return v, p, fmt.Errorf("vertex %q: pos: %w", name, err)
+			}
+			if p.Y, err = strconv.ParseFloat(y, 64); err != nil {
+				return v, p, fmt.Errorf("vertex %q: pos: %w", name, err)
+			}
+			p.Y = -p.Y
+		case dotWidth:
+			if p.Width, err = strconv.ParseFloat(value, 64); err != nil {
+				return v, p, fmt.Errorf("vertex %q: width: %w", name, err)
+			}
+		case dotRow:
+			if p.Row, err = strconv.Atoi(value); err != nil {
+				return v, p, fmt.Errorf("vertex %q: row: %w", name, err)
+			}
+		case dotFinger:
+			p.Finger = value
+		case dotHand:
+			p.Hand = value
+		case dotPress:
+			if v.Weight, err = strconv.Atoi(value); err != nil {
+				return v, p, fmt.Errorf("vertex %q: press: %w", name, err)
+			}
+		case dotLabel:
+			//label is shown by Graphviz instead of the name
+		default:
+			if p.Attributes == nil {
+				p.Attributes = make(map[string]string)
+			}
+			p.Attributes[k] = value
+		}
+	}
+	return v, p, nil
+}
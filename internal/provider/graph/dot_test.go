@@ -0,0 +1,164 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDot(t *testing.T) {
+	t.Run("test dot functions", func(t *testing.T) {
+		var (
+			buf   bytes.Buffer
+			g, g1 Graph[string, Vertex]
+			err   error
+		)
+
+		hash := func(v Vertex) string {
+			return v.Name
+		}
+
+		g = New(hash)
+		_ = g.AddVertex(Vertex{Name: "a", Weight: 2}, VertexPosition(2.25, 1.5), VertexWidth(1), VertexRow(1),
+			VertexFinger("pinky"), VertexHand(HandLeft), VertexAttribute("key cap", "A"))
+		_ = g.AddVertex(Vertex{Name: "s"}, VertexPosition(3.25, 1.5))
+		_ = g.AddVertex(Vertex{Name: ";"})
+		_ = g.AddWeightedEdge("a", "s", 1)
+		_ = g.AddWeightedEdge(";", "s", 3)
+
+		t.Run("WriteDot", func(t *testing.T) {
+			err = WriteDot(&buf, g, "small")
+			assert.NoError(t, err)
+			assert.Equal(t, `graph small {
+	node [shape=box];
+	";";
+	a [pos="2.25,-1.5!", width=1, row=1, finger=pinky, hand=left, press=2, "key cap"=A];
+	s [pos="3.25,-1.5!"];
+	";" -- s [label=3];
+	a -- s [label=1];
+}
+`, buf.String())
+		})
+
+		t.Run("ReadDot", func(t *testing.T) {
+			g1, err = ReadDot(&buf)
+			assert.NoError(t, err)
+			assert.False(t, g1.IsDirected())
+
+			for _, name := range []string{"a", "s", ";"} {
+				v, err := g.Vertex(name)
+				assert.NoError(t, err)
+				v1, err := g1.Vertex(name)
+				assert.NoError(t, err)
+				assert.Equal(t, v, v1)
+
+				p, err := g.VertexProperties(name)
+				assert.NoError(t, err)
+				p1, err := g1.VertexProperties(name)
+				assert.NoError(t, err)
+				assert.Equal(t, p, p1)
+			}
+
+			edges, err := g.Edges()
+			assert.NoError(t, err)
+			edges1, err := g1.Edges()
+			assert.NoError(t, err)
+			assert.Equal(t, edges, edges1)
+		})
+
+		t.Run("WriteDot & ReadDot directed", func(t *testing.T) {
+			d := NewDirected(hash)
+			_ = d.AddVertex(Vertex{Name: "a"})
+			_ = d.AddVertex(Vertex{Name: "b"})
+			_ = d.AddWeightedEdge("a", "b", 1)
+			_ = d.AddWeightedEdge("b", "a", 2)
+
+			buf.Reset()
+			err = WriteDot(&buf, d, "two keys")
+			assert.NoError(t, err)
+			assert.Equal(t, "digraph \"two keys\" {\n\tnode [shape=box];\n\ta;\n\tb;\n\ta -> b [label=1];\n\tb -> a [label=2];\n}\n", buf.String())
+
+			g1, err = ReadDot(&buf)
+			assert.NoError(t, err)
+			assert.True(t, g1.IsDirected())
+
+			m, err := g1.WFI(20)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, m["a"]["b"])
+			assert.Equal(t, 2, m["b"]["a"])
+		})
+
+		t.Run("WriteDot & ReadDot escaped names", func(t *testing.T) {
+			d := NewDirected(hash)
+			_ = d.AddVertex(Vertex{Name: `\`})
+			_ = d.AddVertex(Vertex{Name: `"`})
+			_ = d.AddWeightedEdge(`\`, `"`, 3)
+
+			buf.Reset()
+			err = WriteDot(&buf, d, "")
+			assert.NoError(t, err)
+
+			g1, err = ReadDot(&buf)
+			assert.NoError(t, err)
+
+			m, err := g1.WFI(20)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, m[`\`][`"`])
+		})
+
+		t.Run("ReadDot written by hand", func(t *testing.T) {
+			g1, err = ReadDot(strings.NewReader(`
+# keys of the top row
+strict graph {
+	rankdir = LR
+	node [shape=circle]; edge [color=gray]
+	/* chain of edges
+	   with one weight */
+	q -- w -- e [weight=2]
+	"Caps Lock" [pos="0.875,-1.5"] // comment
+	q -- "Caps Lock"; e
+}`))
+			assert.NoError(t, err)
+
+			vertices, err := g1.Vertices()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Caps Lock", "e", "q", "w"}, vertices)
+
+			m, err := g1.ShortestPaths(20)
+			assert.NoError(t, err)
+			assert.Equal(t, 4, m["q"]["e"])
+			assert.Equal(t, 1, m["Caps Lock"]["q"])
+
+			p, err := g1.VertexProperties("Caps Lock")
+			assert.NoError(t, err)
+			assert.Equal(t, VertexProperties{X: 0.875, Y: 1.5}, p)
+		})
+
+		t.Run("ReadDot errors", func(t *testing.T) {
+			for _, text := range []string{
+				``,
+				`tree { a }`,
+				`graph { a -> b }`,
+				`digraph { a -- b }`,
+				`graph { a -- }`,
+				`graph { subgraph s { a } }`,
+				`graph { a [pos="1"] }`,
+				`graph { a -- b [label=x] }`,
+				`graph { "a }`,
+				`graph { a } b`,
+				`graph { a /* }`,
+			} {
+				_, err = ReadDot(strings.NewReader(text))
+				assert.Error(t, err, text)
+			}
+
+			_, err = ReadDot(strings.NewReader(`graph { a -- b; b -- a }`))
+			assert.ErrorIs(t, err, ErrEdgeAlreadyExists)
+		})
+	})
+}
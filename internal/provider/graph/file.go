@@ -2,25 +2,98 @@ package graph
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 )
 
+// BigramDistanceArray packs distances between letters into 32*32 array.
+// Keys which are not a single low-case letter (enter, punctuation) are skipped
 func BigramDistanceArray(m map[string]map[string]int) []int {
 	res := make([]int, 32*32)
 
 	for k1, v1 := range m {
+		if !isLetter(k1) {
+			continue
+		}
 		for k2, v2 := range v1 {
+			if !isLetter(k2) {
+				continue
+			}
 			res[getIndex(k1[0], k2[0])] = v2
 		}
 	}
 	return res
 }
 
+// BigramDistanceMap unpacks 32*32 array made by BigramDistanceArray back to distances between letters
+func BigramDistanceMap(dist []int) map[string]map[string]int {
+	m := make(map[string]map[string]int, 26)
+
+	for a := uint8('a'); a <= 'z'; a++ {
+		m[string(a)] = make(map[string]int, 26)
+		for b := uint8('a'); b <= 'z'; b++ {
+			m[string(a)][string(b)] = dist[getIndex(a, b)]
+		}
+	}
+	return m
+}
+
+// FromKeyDistanceArray returns distances from key to every letter, indexed by letter offset
+func FromKeyDistanceArray(m map[string]map[string]int, key string) ([]int, error) {
+	res := make([]int, 32)
+
+	row, ok := m[key]
+	if !ok {
+		return nil, fmt.Errorf("key %q: %w", key, ErrVertexNotFound)
+	}
+
+	for k, v := range row {
+		if isLetter(k) {
+			res[symbolOffset(k[0])] = v
+		}
+	}
+	return res, nil
+}
+
+// ToKeyDistanceArray returns distances from every letter to key, indexed by letter offset
+func ToKeyDistanceArray(m map[string]map[string]int, key string) ([]int, error) {
+	res := make([]int, 32)
+
+	if _, ok := m[key]; !ok {
+		return nil, fmt.Errorf("key %q: %w", key, ErrVertexNotFound)
+	}
+
+	for k, row := range m {
+		if isLetter(k) {
+			res[symbolOffset(k[0])] = row[key]
+		}
+	}
+	return res, nil
+}
+
+// KeyWeightArray returns press costs of letters, indexed by letter offset
+func KeyWeightArray(w map[string]int) []int {
+	res := make([]int, 32)
+
+	for k, v := range w {
+		if isLetter(k) {
+			res[symbolOffset(k[0])] = v
+		}
+	}
+	return res
+}
+
+func isLetter(k string) bool {
+	return len(k) == 1 && k[0] >= 'a' && k[0] <= 'z'
+}
+
+func symbolOffset(s uint8) int {
+	return int(s) - int('a')
+}
+
 func getIndex(a, b uint8) int {
-	a0 := int(a) - int('a')
-	b0 := int(b) - int('a')
-	return a0<<5 + b0
+	return symbolOffset(a)<<5 + symbolOffset(b)
 }
 
 func SaveToJson(m []int, filename string) error {
@@ -34,6 +34,56 @@ func TestFile(t *testing.T) {
 			}
 		})
 
+		t.Run("BigramDistanceMap unpacks array", func(t *testing.T) {
+			mr := BigramDistanceMap(dist)
+			assert.Equal(t, 26, len(mr))
+			for _, s = range bigrams {
+				assert.Equal(t, m[string(s[0])][string(s[1])], mr[string(s[0])][string(s[1])])
+			}
+			assert.Equal(t, dist, BigramDistanceArray(mr))
+		})
+
+		t.Run("BigramDistanceArray skips non-letter keys", func(t *testing.T) {
+			mk := map[string]map[string]int{
+				"a":     {"a": 0, "enter": 3},
+				"enter": {"a": 3, "enter": 0},
+			}
+			assert.NotPanics(t, func() {
+				distRes = BigramDistanceArray(mk)
+			})
+			assert.Equal(t, 0, distRes[getIndex('a', 'a')])
+		})
+
+		t.Run("FromKeyDistanceArray & ToKeyDistanceArray", func(t *testing.T) {
+			var keyDist []int
+
+			keyDist, err = FromKeyDistanceArray(m, "a")
+			assert.NoError(t, err)
+			assert.Equal(t, 0, keyDist[symbolOffset('a')])
+			assert.Equal(t, 1, keyDist[symbolOffset('s')])
+			assert.Equal(t, 2, keyDist[symbolOffset('d')])
+
+			keyDist, err = ToKeyDistanceArray(m, "d")
+			assert.NoError(t, err)
+			assert.Equal(t, 2, keyDist[symbolOffset('a')])
+			assert.Equal(t, 1, keyDist[symbolOffset('s')])
+			assert.Equal(t, 0, keyDist[symbolOffset('d')])
+
+			_, err = FromKeyDistanceArray(m, "enter")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+
+			_, err = ToKeyDistanceArray(m, "enter")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+		})
+
+		t.Run("KeyWeightArray", func(t *testing.T) {
+			w := KeyWeightArray(map[string]int{"a": 0, "q": 2, "p": 3, "enter": 5})
+			assert.Equal(t, 32, len(w))
+			assert.Equal(t, 0, w[symbolOffset('a')])
+			assert.Equal(t, 2, w[symbolOffset('q')])
+			assert.Equal(t, 3, w[symbolOffset('p')])
+		})
+
 		t.Run("SaveToJson", func(t *testing.T) {
 			err = SaveToJson(dist, filename)
 			assert.NoError(t, err)
@@ -67,17 +117,17 @@ func getDistMapForTest() map[string]map[string]int {
 	_ = g.AddVertex(v3)
 
 	edge = Edge[string]{
-		v1: g.hash(v1),
-		v2: g.hash(v2),
+		Source: g.hash(v1),
+		Target: g.hash(v2),
 	}
-	_ = g.AddEdge(edge.v1, edge.v2)
+	_ = g.AddEdge(edge.Source, edge.Target)
 
 	edge = Edge[string]{
-		v1: g.hash(v2),
-		v2: g.hash(v3),
+		Source: g.hash(v2),
+		Target: g.hash(v3),
 	}
 
-	_ = g.AddEdge(edge.v1, edge.v2)
+	_ = g.AddEdge(edge.Source, edge.Target)
 
 	m, _ := g.WFI(maxN)
 
@@ -0,0 +1,180 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// StorageVersion is the version of the file storage format
+const StorageVersion = 2
+
+var ErrStorageFormat = errors.New("unsupported storage file")
+
+// FileStorage keeps the graph in memory and saves its snapshot to a JSON file: vertices with
+// their properties and edges, sorted, so the file can be kept under version control.
+// Use it with NewWithStorage or NewDirectedWithStorage, the graph type is not saved
+type FileStorage[K comparable, V Vertex] struct {
+	Storage[K, V]
+	filename string
+	autoSave bool
+	lock     sync.Mutex
+}
+
+type storageSnapshot[K comparable, V Vertex] struct {
+	Version  int                  `json:"version"`
+	Vertices []storedVertex[K, V] `json:"vertices"`
+	Edges    []storedEdge[K]      `json:"edges"`
+}
+
+// storedEdge is the edge with keys it is stored under, an undirected graph
+// stores the same edge under source -> target and target -> source
+type storedEdge[K comparable] struct {
+	From K `json:"from"`
+	To   K `json:"to"`
+	Edge[K]
+}
+
+type storedVertex[K comparable, V Vertex] struct {
+	Hash       K                `json:"hash"`
+	Value      V                `json:"value"`
+	Properties VertexProperties `json:"properties"`
+}
+
+// NewFileStorage returns storage loaded from the file, the storage is empty if the file does not exist.
+// With autoSave every change is written to the file at once, otherwise call Save
+func NewFileStorage[K comparable, V Vertex](filename string, autoSave bool) (*FileStorage[K, V], error) {
+	s := &FileStorage[K, V]{
+		Storage:  newMemoryStorage[K, V](),
+		filename: filename,
+		autoSave: autoSave,
+	}
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.load(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return s, nil
+}
+
+func (s *FileStorage[K, V]) load(data []byte) error {
+	var snapshot storageSnapshot[K, V]
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorageFormat, err)
+	}
+
+	if snapshot.Version != StorageVersion {
+		return fmt.Errorf("%w: version %d, expected %d", ErrStorageFormat, snapshot.Version, StorageVersion)
+	}
+
+	for _, v := range snapshot.Vertices {
+		if err := s.Storage.AddVertex(v.Hash, v.Value); err != nil {
+			return fmt.Errorf("vertex %v: %w", v.Hash, err)
+		}
+		if err := s.Storage.SetVertexProperties(v.Hash, v.Properties); err != nil {
+			return fmt.Errorf("vertex %v: %w", v.Hash, err)
+		}
+	}
+
+	for _, e := range snapshot.Edges {
+		if _, err := s.Storage.Vertex(e.From); err != nil {
+			return fmt.Errorf("edge %v -> %v: %w", e.From, e.To, err)
+		}
+		if _, err := s.Storage.Vertex(e.To); err != nil {
+			return fmt.Errorf("edge %v -> %v: %w", e.From, e.To, err)
+		}
+		if err := s.Storage.AddEdge(e.From, e.To, e.Edge); err != nil {
+			return fmt.Errorf("edge %v -> %v: %w", e.From, e.To, err)
+		}
+	}
+	return nil
+}
+
+// Filename returns the file the storage is saved to
+func (s *FileStorage[K, V]) Filename() string {
+	return s.filename
+}
+
+// Save writes the snapshot to the file, the file is replaced atomically
+func (s *FileStorage[K, V]) Save() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot := storageSnapshot[K, V]{Version: StorageVersion}
+
+	hashes, err := vertices[K, V](s.Storage)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		value, err := s.Storage.Vertex(hash)
+		if err != nil {
+			return err
+		}
+		properties, err := s.Storage.VertexProperties(hash)
+		if err != nil {
+			return err
+		}
+		snapshot.Vertices = append(snapshot.Vertices, storedVertex[K, V]{Hash: hash, Value: value, Properties: properties})
+	}
+
+	for _, hash := range hashes {
+		targets, err := neighbors[K, V](s.Storage, hash)
+		if err != nil {
+			return err
+		}
+
+		for _, target := range targets {
+			edge, err := s.Storage.Edge(hash, target)
+			if err != nil {
+				return err
+			}
+			snapshot.Edges = append(snapshot.Edges, storedEdge[K]{From: hash, To: target, Edge: edge})
+		}
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.filename, data)
+}
+
+func (s *FileStorage[K, V]) saved(err error) error {
+	if err != nil || !s.autoSave {
+		return err
+	}
+	return s.Save()
+}
+
+func (s *FileStorage[K, V]) AddVertex(hash K, value V) error {
+	return s.saved(s.Storage.AddVertex(hash, value))
+}
+
+func (s *FileStorage[K, V]) SetVertexProperties(hash K, properties VertexProperties) error {
+	return s.saved(s.Storage.SetVertexProperties(hash, properties))
+}
+
+func (s *FileStorage[K, V]) AddEdge(source, target K, edge Edge[K]) error {
+	return s.saved(s.Storage.AddEdge(source, target, edge))
+}
+
+func (s *FileStorage[K, V]) RemoveVertex(hash K) error {
+	return s.saved(s.Storage.RemoveVertex(hash))
+}
+
+func (s *FileStorage[K, V]) RemoveEdge(source, target K) error {
+	return s.saved(s.Storage.RemoveEdge(source, target))
+}
@@ -0,0 +1,118 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStorage(t *testing.T) {
+	t.Run("test file storage functions", func(t *testing.T) {
+		var (
+			filename = filepath.Join(t.TempDir(), "graphs", "small.json")
+			hash     = func(v Vertex) string { return v.Name }
+			storage  *FileStorage[string, Vertex]
+			edges    []Edge[string]
+			p        VertexProperties
+			err      error
+		)
+
+		t.Run("NewFileStorage without file", func(t *testing.T) {
+			storage, err = NewFileStorage[string, Vertex](filename, false)
+			assert.NoError(t, err)
+			assert.Equal(t, filename, storage.Filename())
+
+			_, err = os.Stat(filename)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+
+		t.Run("Save", func(t *testing.T) {
+			g := NewWithStorage[string, Vertex](hash, storage)
+			_ = g.AddVertex(Vertex{Name: "a"}, VertexPosition(0.5, 0.5), VertexHand(HandLeft), VertexAttribute("color", "red"))
+			_ = g.AddVertex(Vertex{Name: "b", Weight: 2}, VertexPosition(1.5, 0.5))
+			_ = g.AddVertex(Vertex{Name: "c"})
+			_ = g.AddWeightedEdge("a", "b", 3)
+			_ = g.AddEdge("b", "c")
+
+			edges, err = g.Edges()
+			assert.NoError(t, err)
+
+			err = storage.Save()
+			assert.NoError(t, err)
+
+			entries, err := os.ReadDir(filepath.Dir(filename))
+			assert.NoError(t, err)
+			//no temporary files left
+			assert.Equal(t, 1, len(entries))
+		})
+
+		t.Run("reload", func(t *testing.T) {
+			loaded, err := NewFileStorage[string, Vertex](filename, false)
+			assert.NoError(t, err)
+
+			g := NewWithStorage[string, Vertex](hash, loaded)
+
+			r, err := g.Edges()
+			assert.NoError(t, err)
+			assert.Equal(t, edges, r)
+
+			v, err := g.Vertex("b")
+			assert.NoError(t, err)
+			assert.Equal(t, Vertex{Name: "b", Weight: 2}, v)
+
+			p, err = g.VertexProperties("a")
+			assert.NoError(t, err)
+			assert.Equal(t, VertexProperties{X: 0.5, Y: 0.5, Hand: HandLeft, Attributes: map[string]string{"color": "red"}}, p)
+
+			//both directions of undirected edges are restored
+			m, err := g.ShortestPaths(20)
+			assert.NoError(t, err)
+			assert.Equal(t, 4, m["a"]["c"])
+			assert.Equal(t, 4, m["c"]["a"])
+			assert.Equal(t, 3, m["b"]["a"])
+
+			_, err = g.Validate(20)
+			assert.NoError(t, err)
+		})
+
+		t.Run("autoSave", func(t *testing.T) {
+			storage, err = NewFileStorage[string, Vertex](filename, true)
+			assert.NoError(t, err)
+
+			g := NewWithStorage[string, Vertex](hash, storage)
+			err = g.RemoveEdge("b", "c")
+			assert.NoError(t, err)
+			err = g.RemoveVertex("c")
+			assert.NoError(t, err)
+
+			loaded, err := NewFileStorage[string, Vertex](filename, false)
+			assert.NoError(t, err)
+
+			cnt, err := loaded.VertexCount()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, cnt)
+
+			//the undirected edge is stored in both directions
+			cnt, err = loaded.EdgeCount()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, cnt)
+		})
+
+		t.Run("wrong file", func(t *testing.T) {
+			wrong := filepath.Join(t.TempDir(), "wrong.json")
+
+			_ = os.WriteFile(wrong, []byte(`{"version": 0}`), 0o644)
+			_, err = NewFileStorage[string, Vertex](wrong, false)
+			assert.ErrorIs(t, err, ErrStorageFormat)
+
+			_ = os.WriteFile(wrong, []byte(`{"version": 2, "edges": [{"from": "a", "to": "b", "Source": "a", "Target": "b", "Weight": 1}]}`), 0o644)
+			_, err = NewFileStorage[string, Vertex](wrong, false)
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+		})
+	})
+}
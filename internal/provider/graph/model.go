@@ -8,32 +8,137 @@ var (
 	ErrEdgeNotFound        = errors.New("edge not found")
 	ErrEdgeAlreadyExists   = errors.New("edge already exists")
 	ErrNoVertices          = errors.New("no vertices")
+	ErrNegativeWeight      = errors.New("negative weight")
+	ErrVertexHasEdges      = errors.New("vertex has edges")
 	//ErrEdgeCreatesCycle    = errors.New("edge would create a cycle")
 )
 
 type Vertex struct {
 	Name string
+	// Weight is the cost of pressing the key itself, e.g. for keys at the edge or hard to see
+	Weight int
+}
+
+const (
+	HandLeft  = "left"
+	HandRight = "right"
+)
+
+// VertexProperties describe the key: geometry, row and the finger pressing it, zero values are not set
+type VertexProperties struct {
+	// X, Y - coordinates of the key center in key units
+	X float64 `json:"x,omitempty"`
+	Y float64 `json:"y,omitempty"`
+	// Width of the key in key units
+	Width  float64 `json:"width,omitempty"`
+	Row    int     `json:"row,omitempty"`
+	Finger string  `json:"finger,omitempty"`
+	// Hand is HandLeft or HandRight
+	Hand       string            `json:"hand,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// WithVertexProperties sets all properties at once
+func WithVertexProperties(properties VertexProperties) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		*p = properties
+	}
+}
+
+// VertexPosition sets coordinates of the key center
+func VertexPosition(x, y float64) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.X, p.Y = x, y
+	}
+}
+
+func VertexWidth(width float64) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Width = width
+	}
+}
+
+func VertexRow(row int) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Row = row
+	}
+}
+
+func VertexFinger(finger string) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Finger = finger
+	}
+}
+
+func VertexHand(hand string) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Hand = hand
+	}
+}
+
+// VertexAttribute sets arbitrary attribute of the vertex
+func VertexAttribute(key, value string) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		if p.Attributes == nil {
+			p.Attributes = make(map[string]string)
+		}
+		p.Attributes[key] = value
+	}
 }
 
 type Hash[K comparable, V Vertex] func(V) K
 
 // why not Vertex? becouse ut connects hashes of Vertices
 type Edge[V comparable] struct {
-	v1 V
-	v2 V
+	Source V
+	Target V
+	Weight int
 }
 
 type Graph[K comparable, V Vertex] interface {
-	AddVertex(value V) error
+	// AddVertex adds the vertex, options set its properties, e.g. VertexPosition(1.5, 0.5)
+	AddVertex(value V, options ...func(*VertexProperties)) error
 	Vertex(hash K) (V, error)
+	VertexProperties(hash K) (VertexProperties, error)
+	SetVertexProperties(hash K, properties VertexProperties) error
 	AddEdge(source, target K) error
+	// AddWeightedEdge adds edge with the cost of moving source -> target, AddEdge uses weight 1
+	AddWeightedEdge(source, target K, weight int) error
 	Edge(source, target K) (Edge[V], error)
+	// RemoveVertex removes the vertex, it should have no edges
+	RemoveVertex(hash K) error
+	// RemoveEdge removes the edge, for undirected graph both directions
+	RemoveEdge(source, target K) error
+	// Neighbors returns hashes of vertices reachable from the vertex by one edge
+	Neighbors(hash K) ([]K, error)
+	// Edges returns all edges, every edge of undirected graph once
+	Edges() ([]Edge[K], error)
+	// Size returns the number of edges in the graph, every edge of undirected graph is counted once
+	Size() (int, error)
 	// Order returns the number of vertices in the graph.
 	Order() (int, error)
+	// Vertices returns hashes of all vertices sorted by their string form
+	Vertices() ([]K, error)
+	IsDirected() bool
+	// VertexWeights returns the press cost of every vertex
+	VertexWeights() (map[K]int, error)
 	// WFI
 	WFI(nMax int) (map[K]map[K]int, error)
+	// BFS returns count of edges on shortest paths, weights are ignored. It is O(V*E) against O(V^3) of WFI
+	BFS(nMax int) (map[K]map[K]int, error)
+	// Dijkstra returns shortest paths by weights, it is faster than WFI for sparse graphs
+	Dijkstra(nMax int) (map[K]map[K]int, error)
+	// ShortestPaths returns the same as WFI choosing the fastest algorithm: BFS if all weights are 1,
+	// Dijkstra for sparse graph, WFI for dense graph
+	ShortestPaths(nMax int) (map[K]map[K]int, error)
 	// AdjacencyMapWithMaxWeight
 	AdjacencyMapWithMaxWeight(nMax int) (map[K]map[K]int, error)
+	// Components returns connected components, for directed graph edges are taken in both directions
+	Components() ([][]K, error)
+	// Validate returns *ValidationError if there are isolated vertices, several components
+	// or the shortest path between some vertices is not less than nMax, the "no edge" value of WFI.
+	// Otherwise it returns shortest paths computed for the check, the same as ShortestPaths
+	Validate(nMax int) (map[K]map[K]int, error)
 }
 
 func New[K comparable, V Vertex](hash Hash[K, V]) Graph[K, V] {
@@ -43,3 +148,12 @@ func New[K comparable, V Vertex](hash Hash[K, V]) Graph[K, V] {
 func NewWithStorage[K comparable, V Vertex](hash Hash[K, V], storage Storage[K, V]) Graph[K, V] {
 	return newUndirected(hash, storage)
 }
+
+// NewDirected returns graph where edges source -> target and target -> source are different and have own weights
+func NewDirected[K comparable, V Vertex](hash Hash[K, V]) Graph[K, V] {
+	return NewDirectedWithStorage(hash, newMemoryStorage[K, V]())
+}
+
+func NewDirectedWithStorage[K comparable, V Vertex](hash Hash[K, V], storage Storage[K, V]) Graph[K, V] {
+	return newDirected(hash, storage)
+}
@@ -0,0 +1,302 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+func addVertex[K comparable, V Vertex](storage Storage[K, V], hash K, value V, options []func(*VertexProperties)) error {
+	if err := storage.AddVertex(hash, value); err != nil {
+		return err
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+
+	var p VertexProperties
+	for _, option := range options {
+		option(&p)
+	}
+	return storage.SetVertexProperties(hash, p)
+}
+
+func vertexWeights[K comparable, V Vertex](storage Storage[K, V]) (map[K]int, error) {
+	vertices, err := storage.ListVertices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vertices: %w", err)
+	}
+
+	m := make(map[K]int, len(vertices))
+	for _, hash := range vertices {
+		v, err := storage.Vertex(hash)
+		if err != nil {
+			return nil, err
+		}
+		m[hash] = Vertex(v).Weight
+	}
+	return m, nil
+}
+
+// adjacencyMapWithMaxWeight returns weights of edges source -> target, maxN if there is no such edge
+func adjacencyMapWithMaxWeight[K comparable, V Vertex](storage Storage[K, V], maxN int) (map[K]map[K]int, error) {
+	vertices, err := storage.ListVertices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vertices: %w", err)
+	}
+
+	m := make(map[K]map[K]int)
+
+	for _, vertex := range vertices {
+		m[vertex] = make(map[K]int)
+		for _, vertex2 := range vertices {
+			// zero in the diagonal
+			if vertex == vertex2 {
+				m[vertex][vertex2] = 0
+				continue
+			}
+
+			edge, err := storage.Edge(vertex, vertex2)
+			if err != nil {
+				m[vertex][vertex2] = maxN
+			} else {
+				m[vertex][vertex2] = edge.Weight
+			}
+		}
+	}
+	return m, nil
+}
+
+// wfi finds shortest paths between all vertices with Floyd-Warshall algorithm.
+// Edges are taken source -> target, so for directed graph the result may be asymmetric
+func wfi[K comparable, V Vertex](storage Storage[K, V], maxN int) (map[K]map[K]int, error) {
+	dist, err := adjacencyMapWithMaxWeight(storage, maxN)
+	if err != nil {
+		return nil, err
+	}
+
+	vertices, err := storage.ListVertices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vertices: %w", err)
+	}
+
+	if len(vertices) == 0 {
+		return nil, ErrNoVertices
+	}
+
+	for _, k := range vertices {
+		for _, i := range vertices {
+			for _, j := range vertices {
+				if dist[i][j] > dist[i][k]+dist[k][j] {
+					dist[i][j] = dist[i][k] + dist[k][j]
+				}
+			}
+		}
+	}
+
+	return dist, nil
+}
+
+type neighbour[K comparable] struct {
+	hash   K
+	weight int
+}
+
+// adjacencyList returns outgoing edges of every vertex and whether all edges have weight 1
+func adjacencyList[K comparable, V Vertex](storage Storage[K, V]) ([]K, map[K][]neighbour[K], bool, error) {
+	vertices, err := storage.ListVertices()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to list vertices: %w", err)
+	}
+
+	if len(vertices) == 0 {
+		return nil, nil, false, ErrNoVertices
+	}
+
+	unweighted := true
+	adj := make(map[K][]neighbour[K], len(vertices))
+	for _, source := range vertices {
+		targets, err := storage.Neighbors(source)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		sortKeys(targets)
+
+		for _, target := range targets {
+			edge, err := storage.Edge(source, target)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			adj[source] = append(adj[source], neighbour[K]{hash: target, weight: edge.Weight})
+			unweighted = unweighted && edge.Weight == 1
+		}
+	}
+	return vertices, adj, unweighted, nil
+}
+
+// bfs finds count of edges on shortest paths between all vertices with breadth-first search from every vertex,
+// weights are ignored. Unreachable vertices get maxN as WFI does
+func bfs[K comparable, V Vertex](storage Storage[K, V], maxN int) (map[K]map[K]int, error) {
+	vertices, adj, _, err := adjacencyList(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return bfsAdjacency(vertices, adj, maxN), nil
+}
+
+func bfsAdjacency[K comparable](vertices []K, adj map[K][]neighbour[K], maxN int) map[K]map[K]int {
+	dist := make(map[K]map[K]int, len(vertices))
+
+	for _, source := range vertices {
+		d := make(map[K]int, len(vertices))
+		d[source] = 0
+
+		queue := []K{source}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, n := range adj[v] {
+				if _, ok := d[n.hash]; !ok {
+					d[n.hash] = d[v] + 1
+					queue = append(queue, n.hash)
+				}
+			}
+		}
+
+		dist[source] = fillUnreachable(vertices, d, maxN)
+	}
+	return dist
+}
+
+// dijkstra finds shortest paths between all vertices with Dijkstra algorithm from every vertex,
+// weights should be non-negative. Unreachable vertices get maxN as WFI does
+func dijkstra[K comparable, V Vertex](storage Storage[K, V], maxN int) (map[K]map[K]int, error) {
+	vertices, adj, _, err := adjacencyList(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return dijkstraAdjacency(vertices, adj, maxN), nil
+}
+
+func dijkstraAdjacency[K comparable](vertices []K, adj map[K][]neighbour[K], maxN int) map[K]map[K]int {
+	dist := make(map[K]map[K]int, len(vertices))
+
+	for _, source := range vertices {
+		d := map[K]int{source: 0}
+		done := make(map[K]bool, len(vertices))
+
+		pq := &distanceHeap[K]{{hash: source, weight: 0}}
+		for pq.Len() > 0 {
+			v := heap.Pop(pq).(neighbour[K])
+			if done[v.hash] {
+				continue
+			}
+			done[v.hash] = true
+
+			for _, n := range adj[v.hash] {
+				nd := v.weight + n.weight
+				if old, ok := d[n.hash]; !ok || nd < old {
+					d[n.hash] = nd
+					heap.Push(pq, neighbour[K]{hash: n.hash, weight: nd})
+				}
+			}
+		}
+
+		dist[source] = fillUnreachable(vertices, d, maxN)
+	}
+	return dist
+}
+
+// shortestPaths chooses the algorithm: BFS for unweighted graph, Dijkstra for sparse weighted graph and WFI for dense one
+func shortestPaths[K comparable, V Vertex](storage Storage[K, V], maxN int) (map[K]map[K]int, error) {
+	vertices, adj, unweighted, err := adjacencyList(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if unweighted {
+		return bfsAdjacency(vertices, adj, maxN), nil
+	}
+
+	edges := 0
+	for _, a := range adj {
+		edges += len(a)
+	}
+
+	// V * E * log(V) against V^3
+	n := len(vertices)
+	if edges*int(math.Log2(float64(n))+1) < n*n {
+		return dijkstraAdjacency(vertices, adj, maxN), nil
+	}
+	return wfi(storage, maxN)
+}
+
+// fillUnreachable limits distances by maxN as WFI does, maxN is also set for unreachable vertices
+func fillUnreachable[K comparable](vertices []K, d map[K]int, maxN int) map[K]int {
+	for _, v := range vertices {
+		if n, ok := d[v]; !ok || n > maxN {
+			d[v] = maxN
+		}
+	}
+	return d
+}
+
+type distanceHeap[K comparable] []neighbour[K]
+
+func (h distanceHeap[K]) Len() int           { return len(h) }
+func (h distanceHeap[K]) Less(i, j int) bool { return h[i].weight < h[j].weight }
+func (h distanceHeap[K]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *distanceHeap[K]) Push(x any) {
+	*h = append(*h, x.(neighbour[K]))
+}
+
+func (h *distanceHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+func vertices[K comparable, V Vertex](storage Storage[K, V]) ([]K, error) {
+	res, err := storage.ListVertices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vertices: %w", err)
+	}
+
+	sortKeys(res)
+	return res, nil
+}
+
+// neighbors returns targets of edges going out of the vertex sorted by their string form
+func neighbors[K comparable, V Vertex](storage Storage[K, V], hash K) ([]K, error) {
+	res, err := storage.Neighbors(hash)
+	if err != nil {
+		return nil, fmt.Errorf("could not find vertex with hash %v: %w", hash, err)
+	}
+
+	sortKeys(res)
+	return res, nil
+}
+
+// sortKeys sorts keys by their string form, so results do not depend on the order of maps
+func sortKeys[K comparable](keys []K) {
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+}
+
+func sortEdges[K comparable](edges []Edge[K]) {
+	sort.Slice(edges, func(i, j int) bool {
+		si, sj := fmt.Sprint(edges[i].Source), fmt.Sprint(edges[j].Source)
+		if si != sj {
+			return si < sj
+		}
+		return fmt.Sprint(edges[i].Target) < fmt.Sprint(edges[j].Target)
+	})
+}
@@ -0,0 +1,125 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaths(t *testing.T) {
+	t.Run("test shortest paths", func(t *testing.T) {
+		var (
+			wfiDist, dist map[string]map[string]int
+			err           error
+			maxN          = 20
+		)
+
+		hash := func(v Vertex) string {
+			return v.Name
+		}
+
+		// grid of 6x5 keys, every key is connected with the right and the bottom neighbour
+		grid := func(g Graph[string, Vertex], weight func() int) Graph[string, Vertex] {
+			name := func(r, c int) string {
+				return fmt.Sprintf("%d%d", r, c)
+			}
+			for r := 0; r < 5; r++ {
+				for c := 0; c < 6; c++ {
+					_ = g.AddVertex(Vertex{Name: name(r, c)})
+				}
+			}
+			for r := 0; r < 5; r++ {
+				for c := 0; c < 6; c++ {
+					if c < 5 {
+						_ = g.AddWeightedEdge(name(r, c), name(r, c+1), weight())
+					}
+					if r < 4 {
+						_ = g.AddWeightedEdge(name(r, c), name(r+1, c), weight())
+					}
+				}
+			}
+			return g
+		}
+
+		one := func() int {
+			return 1
+		}
+		rnd := rand.New(rand.NewSource(1))
+		random := func() int {
+			return rnd.Intn(4)
+		}
+
+		t.Run("BFS", func(t *testing.T) {
+			g := grid(New(hash), one)
+
+			wfiDist, err = g.WFI(maxN)
+			assert.NoError(t, err)
+
+			dist, err = g.BFS(maxN)
+			assert.NoError(t, err)
+			assert.Equal(t, wfiDist, dist)
+			assert.Equal(t, 9, dist["00"]["45"])
+
+			// limited by maxN as WFI
+			dist, err = g.BFS(5)
+			assert.NoError(t, err)
+			assert.Equal(t, 5, dist["00"]["45"])
+		})
+
+		t.Run("Dijkstra", func(t *testing.T) {
+			for _, g := range []Graph[string, Vertex]{grid(New(hash), random), grid(NewDirected(hash), random)} {
+				wfiDist, err = g.WFI(maxN)
+				assert.NoError(t, err)
+
+				dist, err = g.Dijkstra(maxN)
+				assert.NoError(t, err)
+				assert.Equal(t, wfiDist, dist)
+			}
+		})
+
+		t.Run("ShortestPaths", func(t *testing.T) {
+			for _, g := range []Graph[string, Vertex]{grid(New(hash), one), grid(New(hash), random), grid(NewDirected(hash), random)} {
+				wfiDist, err = g.WFI(maxN)
+				assert.NoError(t, err)
+
+				dist, err = g.ShortestPaths(maxN)
+				assert.NoError(t, err)
+				assert.Equal(t, wfiDist, dist)
+			}
+
+			// directed grid: only right and down
+			g := grid(NewDirected(hash), one)
+			dist, err = g.ShortestPaths(maxN)
+			assert.NoError(t, err)
+			assert.Equal(t, 9, dist["00"]["45"])
+			assert.Equal(t, maxN, dist["45"]["00"])
+		})
+
+		t.Run("BFS on weighted graph counts edges", func(t *testing.T) {
+			g := New(hash)
+			_ = g.AddVertex(Vertex{Name: "a"})
+			_ = g.AddVertex(Vertex{Name: "b"})
+			_ = g.AddWeightedEdge("a", "b", 3)
+
+			dist, err = g.BFS(maxN)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, dist["a"]["b"])
+
+			dist, err = g.ShortestPaths(maxN)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, dist["a"]["b"])
+		})
+
+		t.Run("no vertices", func(t *testing.T) {
+			_, err = New(hash).BFS(maxN)
+			assert.ErrorIs(t, err, ErrNoVertices)
+			_, err = New(hash).ShortestPaths(maxN)
+			assert.ErrorIs(t, err, ErrNoVertices)
+		})
+	})
+}
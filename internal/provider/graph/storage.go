@@ -7,14 +7,25 @@ type Storage[K comparable, V Vertex] interface {
 	Vertex(hash K) (V, error)
 	AddEdge(source, target K, edge Edge[K]) error
 	Edge(source, target K) (Edge[K], error)
+	// VertexProperties returns properties of the vertex, zero if not set
+	VertexProperties(hash K) (VertexProperties, error)
+	SetVertexProperties(hash K, properties VertexProperties) error
 	VertexCount() (int, error)
 	ListVertices() ([]K, error)
+	// RemoveVertex removes the vertex, it returns ErrVertexHasEdges if the vertex has edges
+	RemoveVertex(hash K) error
+	RemoveEdge(source, target K) error
+	// Neighbors returns targets of edges going out of the vertex
+	Neighbors(hash K) ([]K, error)
+	// ListEdges returns all edges source -> target
+	ListEdges() ([]Edge[K], error)
+	EdgeCount() (int, error)
 }
 
 type memoryStorage[K comparable, V Vertex] struct {
-	lock     sync.RWMutex
-	vertices map[K]V
-	//vertexProperties map[K]VertexProperties
+	lock             sync.RWMutex
+	vertices         map[K]V
+	vertexProperties map[K]VertexProperties
 	//edges map[K]map[K]Edge[K]
 	// outEdges and inEdges store all outgoing and ingoing edges for all vertices. For O(1) access,
 	// these edges themselves are stored in maps whose keys are the hashes of the target vertices.
@@ -24,9 +35,10 @@ type memoryStorage[K comparable, V Vertex] struct {
 
 func newMemoryStorage[K comparable, V Vertex]() Storage[K, V] {
 	return &memoryStorage[K, V]{
-		vertices: make(map[K]V),
-		outEdges: make(map[K]map[K]Edge[K]),
-		inEdges:  make(map[K]map[K]Edge[K]),
+		vertices:         make(map[K]V),
+		vertexProperties: make(map[K]VertexProperties),
+		outEdges:         make(map[K]map[K]Edge[K]),
+		inEdges:          make(map[K]map[K]Edge[K]),
 	}
 }
 
@@ -57,6 +69,30 @@ func (s *memoryStorage[K, T]) Vertex(k K) (T, error) {
 	return v, nil
 }
 
+func (s *memoryStorage[K, T]) VertexProperties(k K) (VertexProperties, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[k]; !ok {
+		return VertexProperties{}, ErrVertexNotFound
+	}
+
+	return s.vertexProperties[k], nil
+}
+
+func (s *memoryStorage[K, T]) SetVertexProperties(k K, p VertexProperties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.vertices[k]; !ok {
+		return ErrVertexNotFound
+	}
+
+	s.vertexProperties[k] = p
+
+	return nil
+}
+
 func (s *memoryStorage[K, T]) AddEdge(sourceHash, targetHash K, edge Edge[K]) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -111,3 +147,79 @@ func (s *memoryStorage[K, T]) ListVertices() ([]K, error) {
 
 	return hashes, nil
 }
+
+func (s *memoryStorage[K, T]) RemoveVertex(k K) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.vertices[k]; !ok {
+		return ErrVertexNotFound
+	}
+
+	if len(s.outEdges[k]) > 0 || len(s.inEdges[k]) > 0 {
+		return ErrVertexHasEdges
+	}
+
+	delete(s.vertices, k)
+	delete(s.vertexProperties, k)
+	delete(s.outEdges, k)
+	delete(s.inEdges, k)
+
+	return nil
+}
+
+func (s *memoryStorage[K, T]) RemoveEdge(sourceHash, targetHash K) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.outEdges[sourceHash][targetHash]; !ok {
+		return ErrEdgeNotFound
+	}
+
+	delete(s.outEdges[sourceHash], targetHash)
+	delete(s.inEdges[targetHash], sourceHash)
+
+	return nil
+}
+
+func (s *memoryStorage[K, T]) Neighbors(k K) ([]K, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[k]; !ok {
+		return nil, ErrVertexNotFound
+	}
+
+	hashes := make([]K, 0, len(s.outEdges[k]))
+	for target := range s.outEdges[k] {
+		hashes = append(hashes, target)
+	}
+
+	return hashes, nil
+}
+
+func (s *memoryStorage[K, T]) ListEdges() ([]Edge[K], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var edges []Edge[K]
+	for _, targets := range s.outEdges {
+		for _, edge := range targets {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges, nil
+}
+
+func (s *memoryStorage[K, T]) EdgeCount() (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	cnt := 0
+	for _, targets := range s.outEdges {
+		cnt += len(targets)
+	}
+
+	return cnt, nil
+}
@@ -95,8 +95,8 @@ func TestStorage(t *testing.T) {
 
 		t.Run("AddEdge", func(t *testing.T) {
 			edge = Edge[string]{
-				v1: h1,
-				v2: h2,
+				Source: h1,
+				Target: h2,
 			}
 			err = storage.AddEdge(h1, h2, edge)
 			assert.NoError(t, err)
@@ -118,5 +118,46 @@ func TestStorage(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, 3, cnt)
 		})
+
+		t.Run("Neighbors & ListEdges & EdgeCount", func(t *testing.T) {
+			l1, err = storage.Neighbors(h1)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{h2}, l1)
+
+			_, err = storage.Neighbors("d")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+
+			edges, err := storage.ListEdges()
+			assert.NoError(t, err)
+			assert.Equal(t, []Edge[string]{edge}, edges)
+
+			cnt, err = storage.EdgeCount()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, cnt)
+		})
+
+		t.Run("RemoveEdge & RemoveVertex", func(t *testing.T) {
+			err = storage.RemoveVertex(h2)
+			assert.ErrorIs(t, err, ErrVertexHasEdges)
+
+			err = storage.RemoveEdge(h2, h1)
+			assert.ErrorIs(t, err, ErrEdgeNotFound)
+
+			err = storage.RemoveEdge(h1, h2)
+			assert.NoError(t, err)
+
+			_, err = storage.Edge(h1, h2)
+			assert.ErrorIs(t, err, ErrEdgeNotFound)
+
+			err = storage.RemoveVertex(h2)
+			assert.NoError(t, err)
+
+			cnt, err = storage.VertexCount()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, cnt)
+
+			err = storage.RemoveVertex(h2)
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+		})
 	})
 }
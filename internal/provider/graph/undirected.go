@@ -17,10 +17,16 @@ func newUndirected[K comparable, V Vertex](hash Hash[K, V], storage Storage[K, V
 	}
 }
 
-func (u *undirected[K, V]) AddVertex(value V) error {
-	hash := u.hash(value)
+func (u *undirected[K, V]) AddVertex(value V, options ...func(*VertexProperties)) error {
+	return addVertex(u.storage, u.hash(value), value, options)
+}
+
+func (u *undirected[K, V]) VertexProperties(hash K) (VertexProperties, error) {
+	return u.storage.VertexProperties(hash)
+}
 
-	return u.storage.AddVertex(hash, value)
+func (u *undirected[K, V]) SetVertexProperties(hash K, properties VertexProperties) error {
+	return u.storage.SetVertexProperties(hash, properties)
 }
 
 func (u *undirected[K, T]) Vertex(hash K) (T, error) {
@@ -29,6 +35,15 @@ func (u *undirected[K, T]) Vertex(hash K) (T, error) {
 }
 
 func (u *undirected[K, V]) AddEdge(source, target K) error {
+	return u.AddWeightedEdge(source, target, 1)
+}
+
+// AddWeightedEdge adds edge with the same weight in both directions
+func (u *undirected[K, V]) AddWeightedEdge(source, target K, weight int) error {
+	if weight < 0 {
+		return ErrNegativeWeight
+	}
+
 	if _, err := u.storage.Vertex(source); err != nil {
 		return fmt.Errorf("could not find source vertex with hash %v: %w", source, err)
 	}
@@ -43,8 +58,9 @@ func (u *undirected[K, V]) AddEdge(source, target K) error {
 	}
 
 	edge := Edge[K]{
-		v1: source,
-		v2: target,
+		Source: source,
+		Target: target,
+		Weight: weight,
 	}
 
 	if err := u.addEdge(source, target, edge); err != nil {
@@ -58,10 +74,9 @@ func (u *undirected[K, V]) Edge(source, target K) (Edge[V], error) {
 	// In an undirected graph, since multigraphs aren't supported, the edge AB is the same as BA.
 	// Therefore, if source[target] cannot be found, this function also looks for target[source].
 
-	//edge, err := u.storage.Edge(source, target)
-	_, err := u.storage.Edge(source, target)
+	edge, err := u.storage.Edge(source, target)
 	if errors.Is(err, ErrEdgeNotFound) {
-		_, err = u.storage.Edge(target, source)
+		edge, err = u.storage.Edge(target, source)
 	}
 
 	if err != nil {
@@ -79,8 +94,9 @@ func (u *undirected[K, V]) Edge(source, target K) (Edge[V], error) {
 	}
 
 	return Edge[V]{
-		v1: sourceVertex,
-		v2: targetVertex,
+		Source: sourceVertex,
+		Target: targetVertex,
+		Weight: edge.Weight,
 	}, nil
 }
 
@@ -91,8 +107,9 @@ func (u *undirected[K, V]) addEdge(sourceHash, targetHash K, edge Edge[K]) error
 	}
 
 	rEdge := Edge[K]{
-		v1: edge.v1,
-		v2: edge.v2,
+		Source: edge.Source,
+		Target: edge.Target,
+		Weight: edge.Weight,
 	}
 
 	err = u.storage.AddEdge(targetHash, sourceHash, rEdge)
@@ -103,62 +120,97 @@ func (u *undirected[K, V]) addEdge(sourceHash, targetHash K, edge Edge[K]) error
 	return nil
 }
 
-func (u *undirected[K, V]) Order() (int, error) {
-	return u.storage.VertexCount()
-}
+// RemoveEdge removes the edge in both directions
+func (u *undirected[K, V]) RemoveEdge(source, target K) error {
+	if _, err := u.Edge(source, target); err != nil {
+		return err
+	}
 
-func (u *undirected[K, V]) AdjacencyMapWithMaxWeight(maxN int) (map[K]map[K]int, error) {
-	vertices, err := u.storage.ListVertices()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list vertices: %w", err)
-	}
-
-	m := make(map[K]map[K]int)
-
-	for _, vertex := range vertices {
-		m[vertex] = make(map[K]int)
-		for _, vertex2 := range vertices {
-			// zero in the diagonal
-			if vertex == vertex2 {
-				m[vertex][vertex2] = 0
-				continue
-			}
-
-			_, err := u.storage.Edge(vertex, vertex2)
-			if err != nil {
-				m[vertex][vertex2] = maxN
-			} else {
-				m[vertex][vertex2] = 1
-			}
-		}
+	if err := u.storage.RemoveEdge(source, target); err != nil && !errors.Is(err, ErrEdgeNotFound) {
+		return fmt.Errorf("failed to remove edge from %v to %v: %w", source, target, err)
+	}
+
+	if err := u.storage.RemoveEdge(target, source); err != nil && !errors.Is(err, ErrEdgeNotFound) {
+		return fmt.Errorf("failed to remove edge from %v to %v: %w", target, source, err)
 	}
-	return m, nil
+
+	return nil
 }
 
-func (u *undirected[K, V]) WFI(maxN int) (map[K]map[K]int, error) {
-	dist, err := u.AdjacencyMapWithMaxWeight(maxN)
+func (u *undirected[K, V]) RemoveVertex(hash K) error {
+	return u.storage.RemoveVertex(hash)
+}
+
+func (u *undirected[K, V]) Neighbors(hash K) ([]K, error) {
+	return neighbors(u.storage, hash)
+}
+
+// Edges returns every edge once, in the direction it was added
+func (u *undirected[K, V]) Edges() ([]Edge[K], error) {
+	edges, err := u.storage.ListEdges()
 	if err != nil {
 		return nil, err
 	}
 
-	vertices, err := u.storage.ListVertices()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list vertices: %w", err)
+	//both directions are stored as the same edge
+	seen := make(map[Edge[K]]bool, len(edges)/2)
+	res := make([]Edge[K], 0, len(edges)/2)
+	for _, e := range edges {
+		if !seen[e] {
+			seen[e] = true
+			res = append(res, e)
+		}
 	}
 
-	if len(vertices) == 0 {
-		return nil, ErrNoVertices
-	}
+	sortEdges(res)
+	return res, nil
+}
 
-	for _, k := range vertices {
-		for _, i := range vertices {
-			for _, j := range vertices {
-				if dist[i][j] > dist[i][k]+dist[k][j] {
-					dist[i][j] = dist[i][k] + dist[k][j]
-				}
-			}
-		}
-	}
+func (u *undirected[K, V]) Size() (int, error) {
+	edges, err := u.Edges()
+	return len(edges), err
+}
+
+func (u *undirected[K, V]) Vertices() ([]K, error) {
+	return vertices(u.storage)
+}
+
+func (u *undirected[K, V]) IsDirected() bool {
+	return false
+}
+
+func (u *undirected[K, V]) Order() (int, error) {
+	return u.storage.VertexCount()
+}
+
+func (u *undirected[K, V]) VertexWeights() (map[K]int, error) {
+	return vertexWeights(u.storage)
+}
+
+func (u *undirected[K, V]) AdjacencyMapWithMaxWeight(maxN int) (map[K]map[K]int, error) {
+	return adjacencyMapWithMaxWeight(u.storage, maxN)
+}
+
+func (u *undirected[K, V]) WFI(maxN int) (map[K]map[K]int, error) {
+	return wfi(u.storage, maxN)
+}
+
+func (u *undirected[K, V]) BFS(maxN int) (map[K]map[K]int, error) {
+	return bfs(u.storage, maxN)
+}
+
+func (u *undirected[K, V]) Dijkstra(maxN int) (map[K]map[K]int, error) {
+	return dijkstra(u.storage, maxN)
+}
+
+func (u *undirected[K, V]) ShortestPaths(maxN int) (map[K]map[K]int, error) {
+	return shortestPaths(u.storage, maxN)
+}
+
+func (u *undirected[K, V]) Components() ([][]K, error) {
+	return components(u.storage)
+}
 
-	return dist, nil
+func (u *undirected[K, V]) Validate(maxN int) (map[K]map[K]int, error) {
+	return validate(u.storage, maxN)
 }
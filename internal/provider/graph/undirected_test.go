@@ -72,25 +72,25 @@ func TestUndirected(t *testing.T) {
 		t.Run("AddEdge", func(t *testing.T) {
 			t.Run("first edge", func(t *testing.T) {
 				edge = Edge[string]{
-					v1: g.hash(v1),
-					v2: g.hash(v2),
+					Source: g.hash(v1),
+					Target: g.hash(v2),
 				}
 
-				err = g.AddEdge(edge.v1, edge.v2)
+				err = g.AddEdge(edge.Source, edge.Target)
 				assert.NoError(t, err)
 
 				t.Run("check direct", func(t *testing.T) {
 					rEdge, err = g.Edge(g.hash(v1), g.hash(v2))
 					assert.NoError(t, err)
-					assert.Equal(t, edge.v1, rEdge.v1.Name)
-					assert.Equal(t, edge.v2, rEdge.v2.Name)
+					assert.Equal(t, edge.Source, rEdge.Source.Name)
+					assert.Equal(t, edge.Target, rEdge.Target.Name)
 				})
 
 				t.Run("check reverse", func(t *testing.T) {
 					rEdge, err = g.Edge(g.hash(v2), g.hash(v1))
 					assert.NoError(t, err)
-					assert.Equal(t, edge.v1, rEdge.v2.Name)
-					assert.Equal(t, edge.v1, rEdge.v2.Name)
+					assert.Equal(t, edge.Source, rEdge.Target.Name)
+					assert.Equal(t, edge.Source, rEdge.Target.Name)
 				})
 
 				t.Run("check nonexistent", func(t *testing.T) {
@@ -108,25 +108,25 @@ func TestUndirected(t *testing.T) {
 				assert.NoError(t, err)
 
 				edge = Edge[string]{
-					v1: g.hash(v2),
-					v2: g.hash(v3),
+					Source: g.hash(v2),
+					Target: g.hash(v3),
 				}
 
-				err = g.AddEdge(edge.v1, edge.v2)
+				err = g.AddEdge(edge.Source, edge.Target)
 				assert.NoError(t, err)
 
 				t.Run("check direct", func(t *testing.T) {
 					rEdge, err = g.Edge(g.hash(v2), g.hash(v3))
 					assert.NoError(t, err)
-					assert.Equal(t, edge.v1, rEdge.v1.Name)
-					assert.Equal(t, edge.v2, rEdge.v2.Name)
+					assert.Equal(t, edge.Source, rEdge.Source.Name)
+					assert.Equal(t, edge.Target, rEdge.Target.Name)
 				})
 
 				t.Run("check reverse", func(t *testing.T) {
 					rEdge, err = g.Edge(g.hash(v3), g.hash(v2))
 					assert.NoError(t, err)
-					assert.Equal(t, edge.v1, rEdge.v2.Name)
-					assert.Equal(t, edge.v1, rEdge.v2.Name)
+					assert.Equal(t, edge.Source, rEdge.Target.Name)
+					assert.Equal(t, edge.Source, rEdge.Target.Name)
 				})
 
 				t.Run("check nonexistent", func(t *testing.T) {
@@ -140,6 +140,24 @@ func TestUndirected(t *testing.T) {
 			})
 		})
 
+		t.Run("VertexWeights", func(t *testing.T) {
+			var w map[string]int
+
+			w, err = g.VertexWeights()
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]int{"a": 0, "b": 0, "c": 0}, w)
+
+			g1 := newUndirected(hash, newMemoryStorage[string]())
+			err = g1.AddVertex(Vertex{Name: "q", Weight: 2})
+			assert.NoError(t, err)
+			err = g1.AddVertex(Vertex{Name: "g"})
+			assert.NoError(t, err)
+
+			w, err = g1.VertexWeights()
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]int{"q": 2, "g": 0}, w)
+		})
+
 		t.Run("AdjacencyMapWithMaxWeight", func(t *testing.T) {
 			var (
 				n  = 20
@@ -190,11 +208,11 @@ func TestUndirected(t *testing.T) {
 
 			t.Run("check after adding edge", func(t *testing.T) {
 				edge = Edge[string]{
-					v1: g.hash(v3),
-					v2: g.hash(v4),
+					Source: g.hash(v3),
+					Target: g.hash(v4),
 				}
 
-				err = g.AddEdge(edge.v1, edge.v2)
+				err = g.AddEdge(edge.Source, edge.Target)
 				assert.NoError(t, err)
 
 				m1, err = g.AdjacencyMapWithMaxWeight(n)
@@ -222,6 +240,39 @@ func TestUndirected(t *testing.T) {
 			})
 		})
 
+		t.Run("test weighted edges for undirected graph", func(t *testing.T) {
+			var (
+				m    map[string]map[string]int
+				maxN = 20
+			)
+
+			g := newUndirected(hash, newMemoryStorage[string]())
+			_ = g.AddVertex(v1)
+			_ = g.AddVertex(v2)
+			_ = g.AddVertex(v3)
+
+			err = g.AddWeightedEdge(hash(v1), hash(v2), 3)
+			assert.NoError(t, err)
+			err = g.AddWeightedEdge(hash(v2), hash(v3), 2)
+			assert.NoError(t, err)
+
+			err = g.AddWeightedEdge(hash(v2), hash(v1), 1)
+			assert.ErrorIs(t, err, ErrEdgeAlreadyExists)
+			err = g.AddWeightedEdge(hash(v1), hash(v3), -1)
+			assert.ErrorIs(t, err, ErrNegativeWeight)
+
+			rEdge, err = g.Edge(hash(v2), hash(v1))
+			assert.NoError(t, err)
+			assert.Equal(t, 3, rEdge.Weight)
+
+			m, err = g.WFI(maxN)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, m[hash(v1)][hash(v2)])
+			assert.Equal(t, 3, m[hash(v2)][hash(v1)])
+			assert.Equal(t, 5, m[hash(v1)][hash(v3)])
+			assert.Equal(t, 5, m[hash(v3)][hash(v1)])
+		})
+
 		t.Run("test WFI for undirected graph", func(t *testing.T) {
 
 			var (
@@ -268,11 +319,11 @@ func TestUndirected(t *testing.T) {
 
 			t.Run("2 vertex + 1 edge graph", func(t *testing.T) {
 				edge = Edge[string]{
-					v1: g.hash(v1),
-					v2: g.hash(v2),
+					Source: g.hash(v1),
+					Target: g.hash(v2),
 				}
 
-				err = g.AddEdge(edge.v1, edge.v2)
+				err = g.AddEdge(edge.Source, edge.Target)
 				assert.NoError(t, err)
 
 				m, err = g.WFI(maxN)
@@ -307,11 +358,11 @@ func TestUndirected(t *testing.T) {
 
 			t.Run("3 vertex + 2 edge graph", func(t *testing.T) {
 				edge = Edge[string]{
-					v1: g.hash(v2),
-					v2: g.hash(v3),
+					Source: g.hash(v2),
+					Target: g.hash(v3),
 				}
 
-				err = g.AddEdge(edge.v1, edge.v2)
+				err = g.AddEdge(edge.Source, edge.Target)
 				assert.NoError(t, err)
 
 				m, err = g.WFI(maxN)
@@ -333,8 +384,8 @@ func TestUndirected(t *testing.T) {
 
 			t.Run("3 vertex + 3 edge graph", func(t *testing.T) {
 				edge = Edge[string]{
-					v1: g.hash(v2),
-					v2: g.hash(v3),
+					Source: g.hash(v2),
+					Target: g.hash(v3),
 				}
 
 				m, err = g.WFI(maxN)
@@ -354,4 +405,146 @@ func TestUndirected(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("test removing and listing for undirected graph", func(t *testing.T) {
+		var (
+			edges []Edge[string]
+			keys  []string
+			size  int
+			err   error
+		)
+
+		g := New(func(v Vertex) string {
+			return v.Name
+		})
+		for _, name := range []string{"a", "b", "c"} {
+			_ = g.AddVertex(Vertex{Name: name})
+		}
+		_ = g.AddEdge("a", "b")
+		_ = g.AddWeightedEdge("c", "b", 2)
+
+		t.Run("Edges & Size", func(t *testing.T) {
+			edges, err = g.Edges()
+			assert.NoError(t, err)
+			assert.Equal(t, []Edge[string]{{Source: "a", Target: "b", Weight: 1}, {Source: "c", Target: "b", Weight: 2}}, edges)
+
+			size, err = g.Size()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, size)
+		})
+
+		t.Run("Neighbors", func(t *testing.T) {
+			keys, err = g.Neighbors("b")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "c"}, keys)
+
+			keys, err = g.Neighbors("a")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"b"}, keys)
+
+			_, err = g.Neighbors("d")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+		})
+
+		t.Run("RemoveVertex with edges", func(t *testing.T) {
+			err = g.RemoveVertex("c")
+			assert.ErrorIs(t, err, ErrVertexHasEdges)
+
+			err = g.RemoveVertex("d")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+		})
+
+		t.Run("RemoveEdge", func(t *testing.T) {
+			// the reverse direction is the same edge
+			err = g.RemoveEdge("b", "c")
+			assert.NoError(t, err)
+
+			_, err = g.Edge("c", "b")
+			assert.ErrorIs(t, err, ErrEdgeNotFound)
+
+			keys, err = g.Neighbors("b")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a"}, keys)
+
+			size, err = g.Size()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, size)
+
+			err = g.RemoveEdge("b", "c")
+			assert.ErrorIs(t, err, ErrEdgeNotFound)
+		})
+
+		t.Run("RemoveVertex", func(t *testing.T) {
+			err = g.RemoveVertex("c")
+			assert.NoError(t, err)
+
+			_, err = g.Vertex("c")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+
+			order, err := g.Order()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, order)
+		})
+	})
+
+	t.Run("test vertex properties", func(t *testing.T) {
+		var (
+			p   VertexProperties
+			err error
+		)
+
+		g := New(func(v Vertex) string {
+			return v.Name
+		})
+
+		t.Run("AddVertex with options", func(t *testing.T) {
+			err = g.AddVertex(Vertex{Name: "f"}, VertexPosition(4.25, 1.5), VertexWidth(1), VertexRow(1),
+				VertexFinger("index"), VertexHand(HandLeft), VertexAttribute("bump", "yes"))
+			assert.NoError(t, err)
+
+			p, err = g.VertexProperties("f")
+			assert.NoError(t, err)
+			assert.Equal(t, VertexProperties{X: 4.25, Y: 1.5, Width: 1, Row: 1, Finger: "index", Hand: HandLeft,
+				Attributes: map[string]string{"bump": "yes"}}, p)
+		})
+
+		t.Run("AddVertex without options", func(t *testing.T) {
+			err = g.AddVertex(Vertex{Name: "g"})
+			assert.NoError(t, err)
+
+			p, err = g.VertexProperties("g")
+			assert.NoError(t, err)
+			assert.Equal(t, VertexProperties{}, p)
+
+			err = g.AddVertex(Vertex{Name: "g"}, VertexRow(2))
+			assert.ErrorIs(t, err, ErrVertexAlreadyExists)
+		})
+
+		t.Run("SetVertexProperties", func(t *testing.T) {
+			err = g.SetVertexProperties("g", VertexProperties{X: 5.25, Y: 1.5, Hand: HandLeft})
+			assert.NoError(t, err)
+
+			p, err = g.VertexProperties("g")
+			assert.NoError(t, err)
+			assert.Equal(t, 5.25, p.X)
+
+			err = g.SetVertexProperties("h", p)
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+
+			_, err = g.VertexProperties("h")
+			assert.ErrorIs(t, err, ErrVertexNotFound)
+		})
+
+		t.Run("RemoveVertex removes properties", func(t *testing.T) {
+			err = g.RemoveVertex("f")
+			assert.NoError(t, err)
+
+			err = g.AddVertex(Vertex{Name: "f"})
+			assert.NoError(t, err)
+
+			p, err = g.VertexProperties("f")
+			assert.NoError(t, err)
+			assert.Equal(t, VertexProperties{}, p)
+		})
+	})
 }
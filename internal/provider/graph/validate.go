@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrIsolatedVertex = errors.New("isolated vertex")
+	ErrDisconnected   = errors.New("graph is disconnected")
+	ErrUnreachable    = errors.New("distance reaches the maximum")
+)
+
+// maxReported limits count of unreachable pairs in the error message
+const maxReported = 5
+
+// ValidationError lists all problems found by Validate, it matches ErrIsolatedVertex,
+// ErrDisconnected and ErrUnreachable with errors.Is
+type ValidationError[K comparable] struct {
+	// Isolated - vertices without edges
+	Isolated []K
+	// Components - connected components, set only if there are more than one
+	Components [][]K
+	// Unreachable - pairs source, target with the shortest path not less than maxN
+	Unreachable [][2]K
+	// MaxN - the "no edge" value used by WFI
+	MaxN int
+}
+
+func (e *ValidationError[K]) Error() string {
+	var parts []string
+
+	if len(e.Isolated) > 0 {
+		parts = append(parts, fmt.Sprintf("%v: %v", ErrIsolatedVertex, e.Isolated))
+	}
+
+	if len(e.Components) > 0 {
+		parts = append(parts, fmt.Sprintf("%v: %d components %v", ErrDisconnected, len(e.Components), e.Components))
+	}
+
+	if len(e.Unreachable) > 0 {
+		pairs := e.Unreachable
+		if len(pairs) > maxReported {
+			pairs = pairs[:maxReported]
+		}
+
+		s := make([]string, 0, len(pairs))
+		for _, p := range pairs {
+			s = append(s, fmt.Sprintf("%v -> %v", p[0], p[1]))
+		}
+		parts = append(parts, fmt.Sprintf("%v %d: %d pairs, e.g. %s", ErrUnreachable, e.MaxN, len(e.Unreachable), strings.Join(s, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *ValidationError[K]) Unwrap() []error {
+	var res []error
+
+	if len(e.Isolated) > 0 {
+		res = append(res, ErrIsolatedVertex)
+	}
+	if len(e.Components) > 0 {
+		res = append(res, ErrDisconnected)
+	}
+	if len(e.Unreachable) > 0 {
+		res = append(res, ErrUnreachable)
+	}
+	return res
+}
+
+// components returns connected components, edges are taken in both directions.
+// Vertices of every component and components are sorted by their string form
+func components[K comparable, V Vertex](storage Storage[K, V]) ([][]K, error) {
+	vertices, err := storage.ListVertices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vertices: %w", err)
+	}
+	sortKeys(vertices)
+
+	parent := make(map[K]K, len(vertices))
+	for _, v := range vertices {
+		parent[v] = v
+	}
+
+	var find func(v K) K
+	find = func(v K) K {
+		if parent[v] != v {
+			parent[v] = find(parent[v])
+		}
+		return parent[v]
+	}
+
+	//edges of directed graph are stored by source, so every edge is found from one of its ends
+	for _, v1 := range vertices {
+		targets, err := storage.Neighbors(v1)
+		if err != nil {
+			return nil, err
+		}
+		for _, v2 := range targets {
+			if r1, r2 := find(v1), find(v2); r1 != r2 {
+				parent[r2] = r1
+			}
+		}
+	}
+
+	var res [][]K
+	index := make(map[K]int)
+	for _, v := range vertices {
+		root := find(v)
+		n, ok := index[root]
+		if !ok {
+			n = len(res)
+			index[root] = n
+			res = append(res, nil)
+		}
+		res[n] = append(res[n], v)
+	}
+	return res, nil
+}
+
+// validate checks that all vertices are connected and all shortest paths are shorter than maxN,
+// returns shortest paths if they are
+func validate[K comparable, V Vertex](storage Storage[K, V], maxN int) (map[K]map[K]int, error) {
+	comps, err := components(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(comps) == 0 {
+		return nil, ErrNoVertices
+	}
+
+	verr := &ValidationError[K]{MaxN: maxN}
+
+	if len(comps) > 1 {
+		verr.Components = comps
+		for _, c := range comps {
+			if len(c) == 1 {
+				verr.Isolated = append(verr.Isolated, c[0])
+			}
+		}
+	}
+
+	dist, err := shortestPaths(storage, maxN)
+	if err != nil {
+		return nil, err
+	}
+
+	vertices := make([]K, 0, len(dist))
+	for _, c := range comps {
+		vertices = append(vertices, c...)
+	}
+	sortKeys(vertices)
+
+	for _, v1 := range vertices {
+		for _, v2 := range vertices {
+			if v1 != v2 && dist[v1][v2] >= maxN {
+				verr.Unreachable = append(verr.Unreachable, [2]K{v1, v2})
+			}
+		}
+	}
+
+	if len(verr.Components) == 0 && len(verr.Unreachable) == 0 {
+		return dist, nil
+	}
+	return nil, verr
+}
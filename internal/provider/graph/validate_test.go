@@ -0,0 +1,110 @@
+//go:build graphTest
+// +build graphTest
+
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("test graph validation", func(t *testing.T) {
+		var (
+			comps [][]string
+			verr  *ValidationError[string]
+			err   error
+			maxN  = 20
+		)
+
+		hash := func(v Vertex) string {
+			return v.Name
+		}
+
+		newGraph := func(g Graph[string, Vertex], names string) Graph[string, Vertex] {
+			for _, r := range names {
+				_ = g.AddVertex(Vertex{Name: string(r)})
+			}
+			return g
+		}
+
+		t.Run("connected", func(t *testing.T) {
+			g := newGraph(New(hash), "abc")
+			_ = g.AddEdge("a", "b")
+			_ = g.AddEdge("c", "b")
+
+			comps, err = g.Components()
+			assert.NoError(t, err)
+			assert.Equal(t, [][]string{{"a", "b", "c"}}, comps)
+
+			dist, err := g.Validate(maxN)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, dist["a"]["c"])
+		})
+
+		t.Run("isolated vertex and components", func(t *testing.T) {
+			g := newGraph(New(hash), "abcde")
+			_ = g.AddEdge("a", "b")
+			_ = g.AddEdge("d", "c")
+
+			comps, err = g.Components()
+			assert.NoError(t, err)
+			assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, comps)
+
+			_, err = g.Validate(maxN)
+			assert.ErrorIs(t, err, ErrIsolatedVertex)
+			assert.ErrorIs(t, err, ErrDisconnected)
+			assert.ErrorIs(t, err, ErrUnreachable)
+
+			assert.True(t, errors.As(err, &verr))
+			assert.Equal(t, []string{"e"}, verr.Isolated)
+			assert.Equal(t, 3, len(verr.Components))
+			// 5*4 pairs, 2 pairs in every component of two are reachable
+			assert.Equal(t, 16, len(verr.Unreachable))
+			assert.Equal(t, [2]string{"a", "c"}, verr.Unreachable[0])
+			assert.Contains(t, err.Error(), "isolated vertex: [e]")
+		})
+
+		t.Run("distance reaches the maximum", func(t *testing.T) {
+			g := newGraph(New(hash), "abc")
+			_ = g.AddWeightedEdge("a", "b", 15)
+			_ = g.AddWeightedEdge("b", "c", 5)
+
+			_, err = g.Validate(maxN)
+			assert.ErrorIs(t, err, ErrUnreachable)
+			assert.NotErrorIs(t, err, ErrDisconnected)
+			assert.NotErrorIs(t, err, ErrIsolatedVertex)
+
+			assert.True(t, errors.As(err, &verr))
+			assert.Equal(t, [][2]string{{"a", "c"}, {"c", "a"}}, verr.Unreachable)
+
+			_, err = g.Validate(21)
+			assert.NoError(t, err)
+		})
+
+		t.Run("directed one way", func(t *testing.T) {
+			g := newGraph(NewDirected(hash), "ab")
+			_ = g.AddEdge("a", "b")
+
+			comps, err = g.Components()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(comps))
+
+			_, err = g.Validate(maxN)
+			assert.ErrorIs(t, err, ErrUnreachable)
+			assert.True(t, errors.As(err, &verr))
+			assert.Equal(t, [][2]string{{"b", "a"}}, verr.Unreachable)
+
+			_ = g.AddEdge("b", "a")
+			_, err = g.Validate(maxN)
+			assert.NoError(t, err)
+		})
+
+		t.Run("no vertices", func(t *testing.T) {
+			_, err = New(hash).Validate(maxN)
+			assert.ErrorIs(t, err, ErrNoVertices)
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package keylog
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+const (
+	DefaultMaxPause = 2000
+	DefaultMinCount = 3
+)
+
+var ErrNoObservations = errors.New("no observed bigrams")
+
+// Calibration estimates personal typing cost of bigrams in milliseconds
+type Calibration struct {
+	// MaxPause - longer intervals between keystrokes are pauses, not typing, ms
+	MaxPause float64
+	// MinCount - bigrams observed fewer times are filled from the graph distance
+	MinCount int
+}
+
+// Result contains cost of all bigrams and the line time = A + B * distance fitted to observed bigrams
+type Result struct {
+	Costs    map[string]map[string]int
+	Observed int
+	A        float64
+	B        float64
+}
+
+// Intervals returns intervals between consecutive keystrokes for every bigram, pauses are skipped
+func (c Calibration) Intervals(events []Event) map[string]map[string][]float64 {
+	res := make(map[string]map[string][]float64)
+
+	for i := 1; i < len(events); i++ {
+		prev, curr := events[i-1], events[i]
+		d := curr.Time - prev.Time
+		if d < 0 || d > c.MaxPause {
+			continue
+		}
+
+		if _, ok := res[prev.Key]; !ok {
+			res[prev.Key] = make(map[string][]float64)
+		}
+		res[prev.Key][curr.Key] = append(res[prev.Key][curr.Key], d)
+	}
+	return res
+}
+
+// Calibrate estimates cost of every bigram of keys from dist as the median interval of the log.
+// Unobserved bigrams are filled by the line fitted to observed medians and graph distances.
+// Cost of the key to itself is zero as in distance maps, repeated keys are not observed
+func (c Calibration) Calibrate(events []Event, dist map[string]map[string]int) (Result, error) {
+	var (
+		xs, ys []float64
+		res    Result
+	)
+
+	medians := make(map[string]map[string]float64)
+	for k1, row := range c.Intervals(events) {
+		if _, ok := dist[k1]; !ok {
+			continue
+		}
+		for k2, intervals := range row {
+			d, ok := dist[k1][k2]
+			if !ok || k1 == k2 || len(intervals) < c.MinCount {
+				continue
+			}
+
+			if _, ok := medians[k1]; !ok {
+				medians[k1] = make(map[string]float64)
+			}
+			m := median(intervals)
+			medians[k1][k2] = m
+
+			xs = append(xs, float64(d))
+			ys = append(ys, m)
+		}
+	}
+
+	if len(xs) == 0 {
+		return res, ErrNoObservations
+	}
+
+	res.Observed = len(xs)
+	res.A, res.B = fitLine(xs, ys)
+	res.Costs = make(map[string]map[string]int, len(dist))
+
+	for k1, row := range dist {
+		res.Costs[k1] = make(map[string]int, len(row))
+		for k2, d := range row {
+			if k1 == k2 {
+				res.Costs[k1][k2] = 0
+				continue
+			}
+
+			v, ok := medians[k1][k2]
+			if !ok {
+				v = res.A + res.B*float64(d)
+			}
+			res.Costs[k1][k2] = int(math.Round(math.Max(v, 0)))
+		}
+	}
+	return res, nil
+}
+
+func median(a []float64) float64 {
+	s := append([]float64{}, a...)
+	sort.Float64s(s)
+
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// fitLine returns least squares line y = a + b*x, b is zero if all x are equal
+func fitLine(xs, ys []float64) (float64, float64) {
+	var sumX, sumY, sumXX, sumXY float64
+
+	n := float64(len(xs))
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXX += xs[i] * xs[i]
+		sumXY += xs[i] * ys[i]
+	}
+
+	den := n*sumXX - sumX*sumX
+	if den == 0 {
+		return sumY / n, 0
+	}
+
+	b := (n*sumXY - sumX*sumY) / den
+	a := (sumY - b*sumX) / n
+	return a, b
+}
@@ -0,0 +1,86 @@
+//go:build keylogTest
+// +build keylogTest
+
+package keylog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalibrate(t *testing.T) {
+	t.Run("test calibrate functions", func(t *testing.T) {
+		var (
+			res Result
+			err error
+		)
+
+		dist := map[string]map[string]int{
+			"a": {"a": 0, "s": 1, "d": 2},
+			"s": {"a": 1, "s": 0, "d": 1},
+			"d": {"a": 2, "s": 1, "d": 0},
+		}
+
+		events, err := ReadFile("testdata/log.csv")
+		assert.NoError(t, err)
+
+		c := Calibration{MaxPause: DefaultMaxPause, MinCount: 1}
+
+		t.Run("Intervals", func(t *testing.T) {
+			intervals := c.Intervals(events)
+			assert.Equal(t, []float64{100, 100, 100, 100}, intervals["a"]["s"])
+			assert.Equal(t, []float64{100, 100}, intervals["s"]["a"])
+			assert.Equal(t, []float64{200}, intervals["s"]["d"])
+			// pause
+			assert.Equal(t, 0, len(intervals["d"]["a"]))
+		})
+
+		t.Run("Calibrate", func(t *testing.T) {
+			res, err = c.Calibrate(events, dist)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, res.Observed)
+
+			assert.Equal(t, 100, res.Costs["a"]["s"])
+			assert.Equal(t, 100, res.Costs["s"]["a"])
+			assert.Equal(t, 200, res.Costs["s"]["d"])
+
+			// line by points (1, 100), (1, 100), (1, 200)
+			assert.InDelta(t, 133.33, res.A, 0.01)
+			assert.Equal(t, 0.0, res.B)
+			assert.Equal(t, 133, res.Costs["a"]["d"])
+			assert.Equal(t, 0, res.Costs["a"]["a"])
+			assert.Equal(t, 3, len(res.Costs))
+
+			// repeated key is not observed, its cost stays zero
+			repeated := append([]Event{{Key: "d", Time: -3000}, {Key: "d", Time: -2900}}, events...)
+			res, err = c.Calibrate(repeated, dist)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, res.Observed)
+			assert.Equal(t, 0, res.Costs["d"]["d"])
+		})
+
+		t.Run("Calibrate with MinCount", func(t *testing.T) {
+			c.MinCount = 3
+			res, err = c.Calibrate(events, dist)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, res.Observed)
+			assert.Equal(t, 100, res.Costs["s"]["d"])
+
+			c.MinCount = 10
+			_, err = c.Calibrate(events, dist)
+			assert.ErrorIs(t, err, ErrNoObservations)
+		})
+
+		t.Run("fitLine", func(t *testing.T) {
+			a, b := fitLine([]float64{0, 1, 2}, []float64{150, 210, 270})
+			assert.InDelta(t, 150, a, 1e-9)
+			assert.InDelta(t, 60, b, 1e-9)
+		})
+
+		t.Run("median", func(t *testing.T) {
+			assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+			assert.Equal(t, 2.5, median([]float64{4, 1, 2, 3}))
+		})
+	})
+}
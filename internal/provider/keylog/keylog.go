@@ -0,0 +1,150 @@
+package keylog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrWrongRecord = errors.New("wrong record")
+	ErrWrongFormat = errors.New("unknown log format")
+)
+
+// Event is one keystroke of the log, Time is in milliseconds
+type Event struct {
+	Key  string
+	Time float64
+}
+
+type jsonEvent struct {
+	Key       string          `json:"key"`
+	Timestamp json.RawMessage `json:"timestamp"`
+}
+
+// ReadFile reads log by the extension of the file: .csv or .jsonl (JSON lines)
+func ReadFile(filename string) ([]Event, error) {
+	var read func(r io.Reader) ([]Event, error)
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		read = ReadCSV
+	case ".jsonl", ".json":
+		read = ReadJSONLines
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrWrongFormat, filename)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return read(file)
+}
+
+// ReadCSV reads records key,timestamp. The first line may be a header
+func ReadCSV(r io.Reader) ([]Event, error) {
+	var res []Event
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := parseTimestamp(record[1])
+		if err != nil {
+			if line == 1 {
+				//header
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		key := normalizeKey(record[0])
+		if key == "" {
+			return nil, fmt.Errorf("line %d: %w: empty key", line, ErrWrongRecord)
+		}
+
+		res = append(res, Event{Key: key, Time: t})
+	}
+	return res, nil
+}
+
+// ReadJSONLines reads records {"key": "a", "timestamp": 1700000000000}, one per line
+func ReadJSONLines(r io.Reader) ([]Event, error) {
+	var res []Event
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var je jsonEvent
+		if err := json.Unmarshal([]byte(text), &je); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		ts := string(je.Timestamp)
+		if s, err := strconv.Unquote(ts); err == nil {
+			ts = s
+		}
+
+		t, err := parseTimestamp(ts)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		key := normalizeKey(je.Key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: %w: empty key", line, ErrWrongRecord)
+		}
+
+		res = append(res, Event{Key: key, Time: t})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseTimestamp parses milliseconds or time in RFC3339 format
+func parseTimestamp(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: timestamp %q", ErrWrongRecord, s)
+	}
+	return float64(t.UnixNano()) / float64(time.Millisecond), nil
+}
+
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,64 @@
+//go:build keylogTest
+// +build keylogTest
+
+package keylog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeylog(t *testing.T) {
+	t.Run("test keylog functions", func(t *testing.T) {
+		var (
+			events []Event
+			err    error
+		)
+
+		t.Run("ReadFile csv", func(t *testing.T) {
+			events, err = ReadFile("testdata/log.csv")
+			assert.NoError(t, err)
+			assert.Equal(t, 9, len(events))
+			assert.Equal(t, Event{Key: "a", Time: 1000}, events[0])
+			assert.Equal(t, Event{Key: "s", Time: 5100}, events[8])
+		})
+
+		t.Run("ReadFile jsonl", func(t *testing.T) {
+			events, err = ReadFile("testdata/log.jsonl")
+			assert.NoError(t, err)
+			assert.Equal(t, []Event{{Key: "a", Time: 1000}, {Key: "s", Time: 1100}, {Key: "d", Time: 1300}}, events)
+		})
+
+		t.Run("ReadFile wrong", func(t *testing.T) {
+			_, err = ReadFile("testdata/log.txt")
+			assert.ErrorIs(t, err, ErrWrongFormat)
+
+			_, err = ReadFile("testdata/nonexistent.csv")
+			assert.Error(t, err)
+		})
+
+		t.Run("ReadCSV wrong records", func(t *testing.T) {
+			_, err = ReadCSV(strings.NewReader("a,1\ns,yesterday\n"))
+			assert.ErrorIs(t, err, ErrWrongRecord)
+
+			_, err = ReadCSV(strings.NewReader("a,1\n,2\n"))
+			assert.ErrorIs(t, err, ErrWrongRecord)
+
+			_, err = ReadCSV(strings.NewReader("a,1,2\n"))
+			assert.Error(t, err)
+		})
+
+		t.Run("ReadJSONLines wrong records", func(t *testing.T) {
+			_, err = ReadJSONLines(strings.NewReader(`{"key": "a", "timestamp": "yesterday"}`))
+			assert.ErrorIs(t, err, ErrWrongRecord)
+
+			_, err = ReadJSONLines(strings.NewReader(`{"timestamp": 1}`))
+			assert.ErrorIs(t, err, ErrWrongRecord)
+
+			_, err = ReadJSONLines(strings.NewReader(`{"key": "a"`))
+			assert.Error(t, err)
+		})
+	})
+}
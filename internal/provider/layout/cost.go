@@ -0,0 +1,199 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Objective is what the password minimizes
+type Objective string
+
+const (
+	// ObjectiveHops - count of moves between neighbour keys in the graph of the connectivity mode
+	ObjectiveHops Objective = "hops"
+	// ObjectiveDistance - distance between key centers in millimetres
+	ObjectiveDistance Objective = "distance"
+	// ObjectiveTime - movement time in milliseconds by Fitts's law
+	ObjectiveTime Objective = "time"
+
+	// DefaultFittsA and DefaultFittsB are constants of Fitts's law for tapping with one finger or stylus, ms
+	DefaultFittsA = 83
+	DefaultFittsB = 127
+)
+
+// Cross is how the keyboard is typed across halves of split keyboard
+type Cross string
+
+const (
+	// CrossFinger - one finger crosses the gap, distances between halves are taken as is
+	CrossFinger Cross = "finger"
+	// CrossHands - every half is typed by its own hand, distances inside halves are calculated without the other half
+	CrossHands Cross = "hands"
+)
+
+var (
+	ErrUnknownObjective = errors.New("unknown objective")
+	ErrUnknownCross     = errors.New("unknown cross model")
+)
+
+// Fitts estimates movement time: MT = A + B * log2(D/W + 1), D - distance to the key, W - width of the key
+type Fitts struct {
+	A float64
+	B float64
+}
+
+// CostModel describes how the cost of the move between two keys is calculated
+type CostModel struct {
+	Objective Objective
+	// Mode is the connectivity mode, used for hops only
+	Mode string
+	// MaxPathLen is the distance between unreachable keys, used for hops only
+	MaxPathLen int
+	// Fitts is used for time only
+	Fitts Fitts
+	// Cross - moves between halves, CrossFinger if not set
+	Cross Cross
+}
+
+func ParseObjective(s string) (Objective, error) {
+	switch o := Objective(s); o {
+	case ObjectiveHops, ObjectiveDistance, ObjectiveTime:
+		return o, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownObjective, s)
+	}
+}
+
+func ParseCross(s string) (Cross, error) {
+	switch c := Cross(s); c {
+	case CrossFinger, CrossHands:
+		return c, nil
+	case "":
+		return CrossFinger, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownCross, s)
+	}
+}
+
+// String describes the model and all its parameters
+func (c CostModel) String() string {
+	var res string
+	switch c.Objective {
+	case ObjectiveHops:
+		res = fmt.Sprintf("%s maxPathLen=%d", c.Objective, c.MaxPathLen)
+	case ObjectiveTime:
+		res = fmt.Sprintf("%s a=%g b=%g", c.Objective, c.Fitts.A, c.Fitts.B)
+	default:
+		res = string(c.Objective)
+	}
+
+	if c.Cross == CrossHands {
+		res += " cross=" + string(c.Cross)
+	}
+	return res
+}
+
+// MoveTime returns movement time between keys by Fitts's law in milliseconds, zero for the same key
+func (f Fitts) MoveTime(from, to Key) float64 {
+	d := from.Distance(to)
+	if d == 0 {
+		return 0
+	}
+
+	w := math.Min(to.Width(), to.Height())
+	return f.A + f.B*math.Log2(d/w+1)
+}
+
+// CostMap returns cost of the move between all keys of the layout, it can be packed with graph.BigramDistanceArray.
+// With CrossHands costs are calculated for keys of every hand separately, moves between hands keep the cost
+// of crossing the gap, so reports of bigrams do not take them as free. Processor FingerModel with one finger
+// per hand does not use them: the other hand is over its key already
+func (l *Layout) CostMap(c CostModel) (map[string]map[string]int, error) {
+	switch c.Cross {
+	case "", CrossFinger:
+		return l.costMap(c)
+	case CrossHands:
+		return l.handsCostMap(c)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCross, c.Cross)
+	}
+}
+
+func (l *Layout) handsCostMap(c CostModel) (map[string]map[string]int, error) {
+	hands := make(map[string][]string, 2)
+	for _, k := range l.Keys {
+		hand := l.KeyHand(k)
+		if hand == "" {
+			return nil, fmt.Errorf("%w: %q", ErrNoHand, k.Name)
+		}
+		hands[hand] = append(hands[hand], k.Name)
+	}
+
+	m, err := l.costMap(c)
+	if err != nil {
+		return nil, err
+	}
+
+	for hand := range hands {
+		var others []string
+		for h, keys := range hands {
+			if h != hand {
+				others = append(others, keys...)
+			}
+		}
+
+		half, err := l.Exclude(others...)
+		if err != nil {
+			return nil, err
+		}
+
+		hm, err := half.costMap(c)
+		if err != nil {
+			return nil, fmt.Errorf("%s hand: %w", hand, err)
+		}
+		for k1, row := range hm {
+			for k2, n := range row {
+				m[k1][k2] = n
+			}
+		}
+	}
+	return m, nil
+}
+
+func (l *Layout) costMap(c CostModel) (map[string]map[string]int, error) {
+	switch c.Objective {
+	case ObjectiveHops:
+		g, err := l.Graph(c.Mode, nil)
+		if err != nil {
+			return nil, err
+		}
+		//a forgotten edge would silently give MaxPathLen as the distance
+		m, err := g.Validate(c.MaxPathLen)
+		if err != nil {
+			return nil, fmt.Errorf("layout %s, mode %s: %w", l.Name, c.Mode, err)
+		}
+		return m, nil
+	case ObjectiveDistance:
+		unit := l.UnitMM()
+		return l.keyMap(func(from, to Key) float64 {
+			return from.Distance(to) * unit
+		}), nil
+	case ObjectiveTime:
+		return l.keyMap(c.Fitts.MoveTime), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownObjective, c.Objective)
+	}
+}
+
+func (l *Layout) keyMap(cost func(from, to Key) float64) map[string]map[string]int {
+	m := make(map[string]map[string]int, len(l.Keys))
+
+	for _, from := range l.Keys {
+		m[from.Name] = make(map[string]int, len(l.Keys))
+		for _, to := range l.Keys {
+			m[from.Name][to.Name] = int(math.Round(cost(from, to)))
+		}
+	}
+	return m
+}
@@ -0,0 +1,147 @@
+//go:build layoutTest
+// +build layoutTest
+
+package layout
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"granny-pass/internal/provider/graph"
+)
+
+func TestCost(t *testing.T) {
+	t.Run("test cost functions", func(t *testing.T) {
+		var (
+			m   map[string]map[string]int
+			err error
+			o   Objective
+		)
+
+		l, err := Load("testdata/small.json")
+		assert.NoError(t, err)
+
+		t.Run("ParseObjective", func(t *testing.T) {
+			for _, s := range []string{"hops", "distance", "time"} {
+				o, err = ParseObjective(s)
+				assert.NoError(t, err)
+				assert.Equal(t, Objective(s), o)
+			}
+
+			_, err = ParseObjective("speed")
+			assert.ErrorIs(t, err, ErrUnknownObjective)
+		})
+
+		t.Run("MoveTime", func(t *testing.T) {
+			f := Fitts{A: 100, B: 200}
+			a, _ := l.Key("a")
+			s, _ := l.Key("s")
+			z, _ := l.Key("z")
+
+			assert.Equal(t, 0.0, f.MoveTime(a, a))
+			// D=1, W=1
+			assert.Equal(t, 300.0, f.MoveTime(a, s))
+			// W is the smallest size of the key
+			d := a.Distance(z)
+			assert.InDelta(t, 100+200*math.Log2(d/1+1), f.MoveTime(a, z), 1e-9)
+		})
+
+		t.Run("CostMap hops", func(t *testing.T) {
+			m, err = l.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, m["a"]["d"])
+			assert.Equal(t, 0, m["d"]["d"])
+
+			_, err = l.CostMap(CostModel{Objective: ObjectiveHops, Mode: "other", MaxPathLen: 20})
+			assert.ErrorIs(t, err, ErrUnknownMode)
+		})
+
+		t.Run("CostMap hops refuses invalid layout", func(t *testing.T) {
+			l1 := *l
+			l1.Edges = map[string][][2]string{ModeTask: {{"a", "s"}, {"s", "d"}}}
+
+			_, err = l1.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20})
+			assert.ErrorIs(t, err, graph.ErrIsolatedVertex)
+			assert.ErrorIs(t, err, graph.ErrDisconnected)
+
+			// a -> d is 2 hops
+			_, err = l.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 2})
+			assert.ErrorIs(t, err, graph.ErrUnreachable)
+		})
+
+		t.Run("CostMap distance", func(t *testing.T) {
+			m, err = l.CostMap(CostModel{Objective: ObjectiveDistance})
+			assert.NoError(t, err)
+			assert.Equal(t, 0, m["a"]["a"])
+			assert.Equal(t, 19, m["a"]["s"])
+			assert.Equal(t, 38, m["a"]["d"])
+			assert.Equal(t, m["d"]["a"], m["a"]["d"])
+		})
+
+		t.Run("CostMap time", func(t *testing.T) {
+			m, err = l.CostMap(CostModel{Objective: ObjectiveTime, Fitts: Fitts{A: DefaultFittsA, B: DefaultFittsB}})
+			assert.NoError(t, err)
+			assert.Equal(t, 0, m["s"]["s"])
+			assert.Equal(t, DefaultFittsA+DefaultFittsB, m["a"]["s"])
+			assert.Equal(t, true, m["a"]["d"] > m["a"]["s"])
+		})
+
+		t.Run("CostModel String", func(t *testing.T) {
+			assert.Equal(t, "hops maxPathLen=20", CostModel{Objective: ObjectiveHops, MaxPathLen: 20}.String())
+			assert.Equal(t, "distance", CostModel{Objective: ObjectiveDistance, MaxPathLen: 20}.String())
+			assert.Equal(t, "time a=83 b=127.5", CostModel{Objective: ObjectiveTime, Fitts: Fitts{A: 83, B: 127.5}}.String())
+			assert.Equal(t, "hops maxPathLen=20", CostModel{Objective: ObjectiveHops, MaxPathLen: 20, Cross: CrossFinger}.String())
+			assert.Equal(t, "hops maxPathLen=20 cross=hands", CostModel{Objective: ObjectiveHops, MaxPathLen: 20, Cross: CrossHands}.String())
+		})
+
+		t.Run("ParseCross", func(t *testing.T) {
+			c, err := ParseCross("")
+			assert.NoError(t, err)
+			assert.Equal(t, CrossFinger, c)
+
+			c, err = ParseCross("hands")
+			assert.NoError(t, err)
+			assert.Equal(t, CrossHands, c)
+
+			_, err = ParseCross("feet")
+			assert.ErrorIs(t, err, ErrUnknownCross)
+		})
+
+		t.Run("CostMap split keyboard", func(t *testing.T) {
+			sl, err := Load("testdata/split.json")
+			assert.NoError(t, err)
+
+			//one finger crosses the gap
+			m, err = sl.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20, Cross: CrossFinger})
+			assert.NoError(t, err)
+			assert.Equal(t, 3, m["a"]["l"])
+
+			m, err = sl.CostMap(CostModel{Objective: ObjectiveDistance})
+			assert.NoError(t, err)
+			assert.Equal(t, int(math.Round(5*19.05)), m["a"]["l"])
+
+			//every half is typed by its own hand
+			m, err = sl.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20, Cross: CrossHands})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, m["a"]["s"])
+			assert.Equal(t, 1, m["l"]["k"])
+			//moves between hands keep the cost of crossing the gap
+			assert.Equal(t, 3, m["a"]["l"])
+			assert.Equal(t, 1, m["k"]["s"])
+
+			//keys of small layout have no hand
+			_, err = l.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20, Cross: CrossHands})
+			assert.ErrorIs(t, err, ErrNoHand)
+
+			_, err = sl.CostMap(CostModel{Objective: ObjectiveHops, Cross: "feet"})
+			assert.ErrorIs(t, err, ErrUnknownCross)
+		})
+
+		t.Run("CostMap unknown", func(t *testing.T) {
+			_, err = l.CostMap(CostModel{Objective: "speed"})
+			assert.ErrorIs(t, err, ErrUnknownObjective)
+		})
+	})
+}
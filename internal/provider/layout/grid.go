@@ -0,0 +1,102 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// GridAlphabet - keys of alphabetical on-screen keyboard
+	GridAlphabet = "abcdefghijklmnopqrstuvwxyz"
+	// GridQwerty - keys of QWERTY on-screen keyboard in the order of rows
+	GridQwerty = "qwertyuiopasdfghjklzxcvbnm"
+
+	// DefaultGridPress is the cost of pressing OK button of the remote
+	DefaultGridPress = 1
+)
+
+var ErrEmptyRow = errors.New("empty row")
+
+// QwertyRows returns rows of QWERTY on-screen keyboard as on the usual keyboard
+func QwertyRows() []string {
+	return []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+}
+
+// GridRows splits keys into rows of the width, the last row may be shorter
+func GridRows(keys string, width int) ([]string, error) {
+	if width < 1 {
+		return nil, fmt.Errorf("grid width %d: %w", width, ErrEmptyRow)
+	}
+
+	var rows []string
+	for len(keys) > width {
+		rows = append(rows, keys[:width])
+		keys = keys[width:]
+	}
+	if keys != "" {
+		rows = append(rows, keys)
+	}
+	return rows, nil
+}
+
+// NewGrid returns on-screen keyboard moved by D-pad: every character of the row is a key,
+// the cursor moves to the neighbour key at the left, right, top or bottom. With wrap the cursor
+// moves from the end of the row or column to its beginning. Press is the cost of pressing OK.
+// Both connectivity modes have the same edges, the cost of the move is the count of D-pad presses
+func NewGrid(name string, rows []string, wrap bool, press int) (*Layout, error) {
+	l := &Layout{
+		Name:  name,
+		Press: press,
+		Edges: make(map[string][][2]string, 2),
+	}
+
+	var edges [][2]string
+	width := 0
+	for y, row := range rows {
+		if row == "" {
+			return nil, fmt.Errorf("row %d: %w", y, ErrEmptyRow)
+		}
+		if len(row) > width {
+			width = len(row)
+		}
+
+		for x := 0; x < len(row); x++ {
+			l.Keys = append(l.Keys, Key{Name: row[x : x+1], X: float64(x), Y: float64(y)})
+			if x > 0 {
+				edges = append(edges, [2]string{row[x-1 : x], row[x : x+1]})
+			}
+		}
+
+		//the move from the last key to the first one is a new edge only if they are not neighbours
+		if wrap && len(row) > 2 {
+			edges = append(edges, [2]string{row[len(row)-1:], row[:1]})
+		}
+	}
+
+	for x := 0; x < width; x++ {
+		//the column ends at the first row which is too short
+		var column []string
+		for _, row := range rows {
+			if x >= len(row) {
+				break
+			}
+			column = append(column, row[x:x+1])
+		}
+
+		for y := 1; y < len(column); y++ {
+			edges = append(edges, [2]string{column[y-1], column[y]})
+		}
+		if wrap && len(column) > 2 {
+			edges = append(edges, [2]string{column[len(column)-1], column[0]})
+		}
+	}
+
+	l.Edges[ModeTask] = edges
+	l.Edges[ModeNormalized] = edges
+
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("grid %s: %w", strings.Join(rows, " "), err)
+	}
+	return l, nil
+}
@@ -0,0 +1,98 @@
+//go:build layoutTest
+// +build layoutTest
+
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid(t *testing.T) {
+	t.Run("test on-screen grid functions", func(t *testing.T) {
+		var (
+			l    *Layout
+			rows []string
+			m    map[string]map[string]int
+			err  error
+			cm   = CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20}
+		)
+
+		t.Run("GridRows", func(t *testing.T) {
+			rows, err = GridRows("abcdefg", 3)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"abc", "def", "g"}, rows)
+
+			_, err = GridRows("abc", 0)
+			assert.ErrorIs(t, err, ErrEmptyRow)
+		})
+
+		t.Run("NewGrid", func(t *testing.T) {
+			rows, _ = GridRows(GridAlphabet, 6)
+			l, err = NewGrid("abc", rows, false, DefaultGridPress)
+			assert.NoError(t, err)
+			assert.Equal(t, 26, len(l.Keys))
+
+			m, err = l.CostMap(cm)
+			assert.NoError(t, err)
+			assert.Equal(t, 5, m["a"]["f"])
+			assert.Equal(t, 5, m["a"]["z"])
+			//z is in the short last row, there is nothing under f
+			assert.Equal(t, 8, m["f"]["z"])
+
+			g, err := l.Graph(ModeNormalized, map[string]int{"z": 1})
+			assert.NoError(t, err)
+			w, err := g.VertexWeights()
+			assert.NoError(t, err)
+			assert.Equal(t, DefaultGridPress, w["a"])
+			assert.Equal(t, DefaultGridPress+1, w["z"])
+		})
+
+		t.Run("NewGrid with wrap", func(t *testing.T) {
+			l, err = NewGrid("abc", rows, true, DefaultGridPress)
+			assert.NoError(t, err)
+
+			m, err = l.CostMap(cm)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, m["a"]["f"])
+			assert.Equal(t, 2, m["a"]["z"])
+		})
+
+		t.Run("NewGrid QWERTY", func(t *testing.T) {
+			l, err = NewGrid("qwerty", QwertyRows(), false, 0)
+			assert.NoError(t, err)
+
+			m, err = l.CostMap(cm)
+			assert.NoError(t, err)
+			assert.Equal(t, 8, m["q"]["m"])
+			assert.Equal(t, 1, m["o"]["l"])
+			//nothing under p
+			assert.Equal(t, 2, m["p"]["l"])
+		})
+
+		t.Run("bundled grids", func(t *testing.T) {
+			l, err = Load("../../../layouts/tv_abc.json")
+			assert.NoError(t, err)
+			rows, _ = GridRows(GridAlphabet, 6)
+			g, _ := NewGrid("tv_abc", rows, true, DefaultGridPress)
+			assert.Equal(t, g, l)
+
+			l, err = Load("../../../layouts/tv_qwerty.json")
+			assert.NoError(t, err)
+			g, _ = NewGrid("tv_qwerty", QwertyRows(), false, DefaultGridPress)
+			assert.Equal(t, g, l)
+		})
+
+		t.Run("NewGrid errors", func(t *testing.T) {
+			_, err = NewGrid("wrong", []string{"abc", "", "d"}, false, 0)
+			assert.ErrorIs(t, err, ErrEmptyRow)
+
+			_, err = NewGrid("wrong", []string{"abc", "cd"}, false, 0)
+			assert.ErrorIs(t, err, ErrDuplicateKey)
+
+			_, err = NewGrid("wrong", []string{"abc"}, false, -1)
+			assert.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package layout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrKLCSyntax = errors.New("wrong KLC data")
+
+// klcLevels maps shift states of KLC columns to levels of keys: base, Shift, AltGr (Ctrl+Alt) and Shift+AltGr
+var klcLevels = map[int]int{0: 0, 1: 1, 6: 2, 7: 3}
+
+// klcSections are keywords starting sections of KLC file
+var klcSections = map[string]bool{
+	"KBD": true, "COPYRIGHT": true, "COMPANY": true, "LOCALENAME": true, "LOCALEID": true, "VERSION": true,
+	"ATTRIBUTES": true, "SHIFTSTATE": true, "LAYOUT": true, "DEADKEY": true, "LIGATURE": true, "KEYNAME": true,
+	"KEYNAME_EXT": true, "KEYNAME_DEAD": true, "DESCRIPTIONS": true, "LANGUAGENAMES": true, "ENDKBD": true,
+}
+
+// klcColumn is the column of the LAYOUT line by the virtual key and the index of the shift state
+type klcColumn struct {
+	vk    string
+	index int
+}
+
+// klcLevel is the level of the key
+type klcLevel struct {
+	code  string
+	level int
+}
+
+// ParseKLC parses Microsoft Keyboard Layout Creator file in UTF-16 or UTF-8. Characters of the LAYOUT section
+// are placed on keys of the standard geometry by scan codes, columns of Shift, AltGr and Shift+AltGr states
+// are levels of keys, dead keys are kept as their characters, %% columns are characters of the LIGATURE section.
+// Empty geometry is chosen by the layout: ISO if the extra key at the left shift has characters, ANSI otherwise
+func ParseKLC(data []byte, name, geometry string) (*Layout, error) {
+	text, err := klcText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make(map[int]string)
+	for _, sk := range standardKeys() {
+		codes[sk.scan] = sk.code
+	}
+
+	var (
+		section string
+		columns []int // shift states of columns after scan code, virtual key and caps
+		chars   = make(map[string][]string)
+		//%% columns are filled from the LIGATURE section after the LAYOUT one
+		ligatureLevels = make(map[klcColumn]klcLevel)
+		ligatures      = make(map[klcColumn]string)
+	)
+
+	for n, line := range strings.Split(text, "\n") {
+		fields := klcFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if klcSections[fields[0]] {
+			section = fields[0]
+			continue
+		}
+
+		switch section {
+		case "SHIFTSTATE":
+			state, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: shift state %q", ErrKLCSyntax, n+1, fields[0])
+			}
+			columns = append(columns, state)
+
+		case "LAYOUT":
+			//the second line of SGCap keys starts with -1
+			scan, err := strconv.ParseInt(fields[0], 16, 32)
+			if err != nil {
+				continue
+			}
+			code, ok := codes[int(scan)]
+			if !ok {
+				continue
+			}
+
+			levels := make([]string, maxLevels)
+			for i, state := range columns {
+				level, ok := klcLevels[state]
+				if !ok || 3+i >= len(fields) {
+					continue
+				}
+				if fields[3+i] == "%%" {
+					ligatureLevels[klcColumn{fields[1], i}] = klcLevel{code, level}
+					continue
+				}
+				levels[level] = klcChar(fields[3+i])
+			}
+			chars[code] = levels
+
+		case "LIGATURE":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("%w: line %d: ligature %q", ErrKLCSyntax, n+1, line)
+			}
+			index, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: ligature shift state %q", ErrKLCSyntax, n+1, fields[1])
+			}
+			var sb strings.Builder
+			for _, f := range fields[2:] {
+				sb.WriteString(klcChar(f))
+			}
+			ligatures[klcColumn{fields[0], index}] = sb.String()
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: no SHIFTSTATE", ErrKLCSyntax)
+	}
+
+	//a column without the ligature has no character
+	for c, l := range ligatureLevels {
+		chars[l.code][l.level] = ligatures[c]
+	}
+
+	return standardLayout(name, geometry, chars)
+}
+
+// klcText decodes UTF-16 file with the byte order mark, other files are UTF-8
+func klcText(data []byte) (string, error) {
+	var order func([]byte) uint16
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		order = func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 }
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		order = func(b []byte) uint16 { return uint16(b[1]) | uint16(b[0])<<8 }
+	default:
+		data = bytes.TrimPrefix(data, []byte{0xef, 0xbb, 0xbf})
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("%w: not UTF-8 or UTF-16", ErrKLCSyntax)
+		}
+		return strings.ReplaceAll(string(data), "\r", ""), nil
+	}
+
+	data = data[2:]
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, order(data[i:i+2]))
+	}
+	return strings.ReplaceAll(string(utf16.Decode(units)), "\r", ""), nil
+}
+
+// klcFields returns fields of the line without comments, a single slash is the character
+func klcFields(line string) []string {
+	fields := strings.Fields(line)
+	for i, f := range fields {
+		if strings.HasPrefix(f, "//") {
+			return fields[:i]
+		}
+	}
+	return fields
+}
+
+// klcChar returns the character of the layout column: the character itself or its hex code,
+// -1 for none, dead keys have @ at the end. %% is the ligature, it is not a character
+func klcChar(s string) string {
+	switch {
+	case s == "-1", s == "%%":
+		return ""
+	case utf8.RuneCountInString(s) == 1:
+		return s
+	}
+
+	s = strings.TrimSuffix(s, "@")
+	if utf8.RuneCountInString(s) == 1 {
+		return s
+	}
+	if n, err := strconv.ParseUint(s, 16, 32); err == nil && n <= utf8.MaxRune {
+		return string(rune(n))
+	}
+	return ""
+}
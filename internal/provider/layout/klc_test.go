@@ -0,0 +1,89 @@
+//go:build layoutTest
+// +build layoutTest
+
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKLC(t *testing.T) {
+	t.Run("test KLC import functions", func(t *testing.T) {
+		var (
+			l   *Layout
+			k   Key
+			err error
+		)
+
+		t.Run("Load .klc in UTF-16", func(t *testing.T) {
+			l, err = Load("testdata/small.klc")
+			assert.NoError(t, err)
+			assert.Equal(t, "small", l.Name)
+			//space and keys outside of the geometry are skipped
+			assert.Equal(t, 11, len(l.Keys))
+
+			k, _ = l.Key("a")
+			assert.Equal(t, Key{Name: "a", X: 1.5, Y: 1, Levels: []string{"A"}}, k)
+
+			//hex codes, AltGr column and dead keys
+			k, _ = l.Key("é")
+			assert.Equal(t, Key{Name: "é", X: 2, Y: 0, Levels: []string{"2", "~"}}, k)
+			k, _ = l.Key("e")
+			assert.Equal(t, []string{"E", "€"}, k.Levels)
+
+			//SGCap line is skipped
+			k, _ = l.Key("s")
+			assert.Equal(t, []string{"S"}, k.Levels)
+
+			//%% is the ligature, the percent sign is 0025
+			k, _ = l.Key("!")
+			assert.Equal(t, []string{"ff"}, k.Levels)
+			k, _ = l.Key(",")
+			assert.Equal(t, []string{"?", "%"}, k.Levels)
+		})
+
+		t.Run("ISO geometry", func(t *testing.T) {
+			k, _ = l.Key("<")
+			assert.Equal(t, Key{Name: "<", X: 1.25, Y: 3, Levels: []string{">"}}, k)
+
+			task := l.Edges[ModeTask]
+			assert.Contains(t, task, [2]string{"a", "q"})
+			assert.Contains(t, task, [2]string{"<", "w"})
+			assert.Contains(t, task, [2]string{"q", "<"})
+		})
+
+		t.Run("UTF-8 and wrong data", func(t *testing.T) {
+			data := "SHIFTSTATE\n0\n1\nLAYOUT\n10\tQ\t1\tq\tQ\n11\tW\t1\tw\tW // comment\n"
+			l, err = ParseKLC([]byte(data), "tiny", GeometryANSI)
+			assert.NoError(t, err)
+			assert.Equal(t, [][2]string{{"q", "w"}}, l.Edges[ModeTask])
+
+			_, err = ParseKLC([]byte("LAYOUT\n10\tQ\t1\tq\tQ\n"), "tiny", "")
+			assert.ErrorIs(t, err, ErrKLCSyntax)
+
+			_, err = ParseKLC([]byte{0xff, 0x00, 0xfe}, "tiny", "")
+			assert.ErrorIs(t, err, ErrKLCSyntax)
+
+			//%% without the ligature is no character
+			l, err = ParseKLC([]byte("SHIFTSTATE\n0\n1\nLAYOUT\n10\tQ\t1\tq\t%%\n11\tW\t1\tw\tW\n"), "tiny", GeometryANSI)
+			assert.NoError(t, err)
+			k, _ = l.Key("q")
+			assert.Empty(t, k.Levels)
+
+			_, err = ParseKLC([]byte("SHIFTSTATE\n0\nLAYOUT\n10\tQ\t1\tq\nLIGATURE\nQ\tx\t0066\n"), "tiny", "")
+			assert.ErrorIs(t, err, ErrKLCSyntax)
+		})
+
+		t.Run("klcChar", func(t *testing.T) {
+			assert.Equal(t, "q", klcChar("q"))
+			assert.Equal(t, "1", klcChar("1"))
+			assert.Equal(t, "é", klcChar("00e9"))
+			assert.Equal(t, "^", klcChar("005e@"))
+			assert.Equal(t, "%", klcChar("0025"))
+			assert.Equal(t, "", klcChar("%%"))
+			assert.Equal(t, "", klcChar("-1"))
+		})
+	})
+}
@@ -0,0 +1,235 @@
+package layout
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrKLESyntax = errors.New("wrong KLE data")
+
+// kleProps are properties of keyboard-layout-editor which change the position and the size of keys
+type kleProps struct {
+	X  *float64 `json:"x"`
+	Y  *float64 `json:"y"`
+	W  *float64 `json:"w"`
+	H  *float64 `json:"h"`
+	R  *float64 `json:"r"`
+	RX *float64 `json:"rx"`
+	RY *float64 `json:"ry"`
+}
+
+type kleMeta struct {
+	Name string `json:"name"`
+}
+
+// ParseKLE parses raw data of keyboard-layout-editor.com: downloaded JSON or text of Raw data tab
+// with unquoted property names. Key names are the lowest legends in lower case, keys without legends
+// are named by their number, repeated names get a number suffix, e.g. shift and shift_2.
+// Rotated keys are placed by their rotated centers. Edges are derived from geometry: in ModeTask
+// the key is connected to its neighbours in the row and to the key below with the largest overlap,
+// in ModeNormalized to all keys below touching it
+func ParseKLE(data []byte, name string) (*Layout, error) {
+	rows, err := kleRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{Name: name, Edges: make(map[string][][2]string, 2)}
+
+	var (
+		x, y, rx, ry, r float64
+		w, h            = 1.0, 1.0
+		used            = make(map[string]int)
+	)
+
+	for n, raw := range rows {
+		if n == 0 && len(raw) > 0 && raw[0] == '{' {
+			var meta kleMeta
+			if err = json.Unmarshal(raw, &meta); err != nil {
+				return nil, fmt.Errorf("%w: metadata: %v", ErrKLESyntax, err)
+			}
+			if meta.Name != "" {
+				l.Name = meta.Name
+			}
+			continue
+		}
+
+		var items []json.RawMessage
+		if err = json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrKLESyntax, n, err)
+		}
+
+		for _, item := range items {
+			if len(item) > 0 && item[0] == '{' {
+				var p kleProps
+				if err = json.Unmarshal(item, &p); err != nil {
+					return nil, fmt.Errorf("%w: row %d: %v", ErrKLESyntax, n, err)
+				}
+
+				if p.R != nil {
+					r = *p.R
+				}
+				//the rotation origin starts a new block of rows
+				if p.RX != nil {
+					rx = *p.RX
+				}
+				if p.RY != nil {
+					ry = *p.RY
+				}
+				if p.RX != nil || p.RY != nil {
+					x, y = rx, ry
+				}
+				if p.X != nil {
+					x += *p.X
+				}
+				if p.Y != nil {
+					y += *p.Y
+				}
+				if p.W != nil {
+					w = *p.W
+				}
+				if p.H != nil {
+					h = *p.H
+				}
+				continue
+			}
+
+			var legend string
+			if err = json.Unmarshal(item, &legend); err != nil {
+				return nil, fmt.Errorf("%w: row %d: %v", ErrKLESyntax, n, err)
+			}
+
+			k := Key{Name: kleName(legend, len(l.Keys)+1, used), X: x, Y: y}
+			if w != 1 {
+				k.W = w
+			}
+			if h != 1 {
+				k.H = h
+			}
+			if r != 0 {
+				cx, cy := rotate(x+w/2, y+h/2, rx, ry, r)
+				k.X, k.Y = round(cx-w/2), round(cy-h/2)
+			}
+			l.Keys = append(l.Keys, k)
+
+			x += w
+			w, h = 1, 1
+		}
+
+		y++
+		x = rx
+	}
+
+	l.Edges[ModeTask], l.Edges[ModeNormalized] = geometryEdges(l.Keys)
+
+	if err = l.Validate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// kleRows returns rows of KLE data as JSON, names of properties are quoted and the outer array is added if needed
+func kleRows(data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(quoteKLENames(data))
+
+	var rows []json.RawMessage
+	if err := json.Unmarshal(data, &rows); err == nil && len(rows) > 0 && isKLERow(rows[len(rows)-1]) {
+		return rows, nil
+	}
+
+	//Raw data tab lists rows without the outer array
+	if err := json.Unmarshal(append(append([]byte("["), data...), ']'), &rows); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKLESyntax, err)
+	}
+	return rows, nil
+}
+
+func isKLERow(raw json.RawMessage) bool {
+	return len(raw) > 0 && raw[0] == '['
+}
+
+// quoteKLENames quotes property names of JavaScript objects, e.g. {x:1} -> {"x":1}
+func quoteKLENames(data []byte) []byte {
+	var (
+		res      = make([]byte, 0, len(data)+16)
+		inString bool
+	)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			res = append(res, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				res = append(res, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+			res = append(res, c)
+			continue
+		}
+
+		if isIdentStart(c) {
+			j := i
+			for j < len(data) && (isIdentStart(data[j]) || (data[j] >= '0' && data[j] <= '9')) {
+				j++
+			}
+			k := j
+			for k < len(data) && (data[k] == ' ' || data[k] == '\t') {
+				k++
+			}
+			if k < len(data) && data[k] == ':' {
+				res = append(res, '"')
+				res = append(res, data[i:j]...)
+				res = append(res, '"')
+			} else {
+				res = append(res, data[i:j]...)
+			}
+			i = j - 1
+			continue
+		}
+
+		res = append(res, c)
+	}
+	return res
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// kleName returns name of the key by its legends separated by new lines
+func kleName(legend string, n int, used map[string]int) string {
+	name := ""
+	for _, s := range strings.Split(legend, "\n") {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			name = s
+		}
+	}
+	if name == "" {
+		name = "key" + strconv.Itoa(n)
+	}
+	return uniqueName(name, used)
+}
+
+// rotate rotates the point around the origin by angle in degrees clockwise, y goes down
+func rotate(x, y, ox, oy, angle float64) (float64, float64) {
+	a := angle * math.Pi / 180
+	dx, dy := x-ox, y-oy
+	return ox + dx*math.Cos(a) - dy*math.Sin(a), oy + dx*math.Sin(a) + dy*math.Cos(a)
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,87 @@
+//go:build layoutTest
+// +build layoutTest
+
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKLE(t *testing.T) {
+	t.Run("test KLE import functions", func(t *testing.T) {
+		var (
+			l   *Layout
+			k   Key
+			m   map[string]map[string]int
+			err error
+		)
+
+		t.Run("Load .kle", func(t *testing.T) {
+			l, err = Load("testdata/small.kle")
+			assert.NoError(t, err)
+			assert.Equal(t, "tiny", l.Name)
+			assert.Equal(t, 10, len(l.Keys))
+
+			k, _ = l.Key("a")
+			assert.Equal(t, Key{Name: "a", X: 0.25, Y: 1}, k)
+
+			k, _ = l.Key("shift")
+			assert.Equal(t, Key{Name: "shift", X: 0.75, Y: 2, W: 2}, k)
+		})
+
+		t.Run("rotation and legends", func(t *testing.T) {
+			//the lowest legend of !/1 key, the rotated center is kept
+			k, _ = l.Key("1")
+			x, y := k.Center()
+			assert.InDelta(t, 4.5, x, 1e-9)
+			assert.InDelta(t, 1.0, y, 1e-9)
+
+			//key without legends
+			_, ok := l.Key("key10")
+			assert.True(t, ok)
+		})
+
+		t.Run("edges from geometry", func(t *testing.T) {
+			task := l.Edges[ModeTask]
+			assert.Contains(t, task, [2]string{"q", "w"})
+			assert.Contains(t, task, [2]string{"q", "a"})
+			assert.Contains(t, task, [2]string{"s", "shift"})
+			assert.Contains(t, task, [2]string{"shift", "z"})
+			//diagonal
+			assert.NotContains(t, task, [2]string{"w", "a"})
+
+			normalized := l.Edges[ModeNormalized]
+			assert.Contains(t, normalized, [2]string{"w", "a"})
+			assert.Contains(t, normalized, [2]string{"d", "z"})
+			assert.NotContains(t, normalized, [2]string{"q", "s"})
+		})
+
+		t.Run("ParseKLE downloaded JSON", func(t *testing.T) {
+			l, err = ParseKLE([]byte(`[["Q","W"],[{"x":0.25},"A","S"]]`), "json")
+			assert.NoError(t, err)
+			assert.Equal(t, "json", l.Name)
+			assert.Equal(t, 4, len(l.Keys))
+
+			m, err = l.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, m["q"]["s"])
+		})
+
+		t.Run("repeated legends", func(t *testing.T) {
+			l, err = ParseKLE([]byte(`["Shift","Z","Shift"]`), "shifts")
+			assert.NoError(t, err)
+			_, ok := l.Key("shift_2")
+			assert.True(t, ok)
+		})
+
+		t.Run("wrong data", func(t *testing.T) {
+			_, err = ParseKLE([]byte(`["Q",{x:}]`), "wrong")
+			assert.ErrorIs(t, err, ErrKLESyntax)
+
+			_, err = ParseKLE([]byte(`[[1]]`), "wrong")
+			assert.ErrorIs(t, err, ErrKLESyntax)
+		})
+	})
+}
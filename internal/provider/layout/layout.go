@@ -0,0 +1,564 @@
+package layout
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"strings"
+
+	"granny-pass/internal/provider/graph"
+)
+
+const (
+	// ModeTask - only horizontal and vertical connections of buttons, as in the task
+	ModeTask = "task"
+	// ModeNormalized - natural movement of one-finger typing method
+	ModeNormalized = "normalized"
+
+	// defaultUnit is the size of 1u key in millimetres
+	defaultUnit = 19.05
+)
+
+var (
+	ErrUnknownMode    = errors.New("unknown connectivity mode")
+	ErrUnknownKey     = errors.New("unknown key")
+	ErrDuplicateKey   = errors.New("duplicate key")
+	ErrNoKeys         = errors.New("no keys")
+	ErrUnknownHand    = errors.New("unknown hand")
+	ErrUnknownCluster = errors.New("unknown cluster")
+	ErrNoHand         = errors.New("key is not assigned to a hand")
+	ErrNoFinger       = errors.New("key is not assigned to a finger")
+	ErrWeightedEdge   = errors.New("layout edges have no weights")
+	ErrKeyPress       = errors.New("keys of the layout have the same press cost")
+)
+
+// Key is a button of the keyboard. Coordinates of the top left corner and sizes are in key units (u)
+type Key struct {
+	Name string  `json:"name"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	W    float64 `json:"w,omitempty"` // 1 by default
+	H    float64 `json:"h,omitempty"` // 1 by default
+	// Finger and Hand pressing the key, e.g. index and left, optional
+	Finger string `json:"finger,omitempty"`
+	Hand   string `json:"hand,omitempty"`
+	// Cluster of the key, in the file coordinates of the key are relative to the cluster origin
+	Cluster string `json:"cluster,omitempty"`
+	// Levels - characters typed with Shift, AltGr and Shift+AltGr, the name is the base character, optional
+	Levels []string `json:"levels,omitempty"`
+}
+
+// Cluster is a group of keys placed together: a half of split keyboard or a thumb cluster
+type Cluster struct {
+	// X, Y - origin of the cluster in key units
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	// Hand typing keys of the cluster which have no own hand
+	Hand string `json:"hand,omitempty"`
+}
+
+// Layout describes keys of the keyboard and connections between them for every connectivity mode
+type Layout struct {
+	Name  string                 `json:"name"`
+	Unit  float64                `json:"unit,omitempty"`  // size of 1u in millimetres, 19.05 by default
+	Press int                    `json:"press,omitempty"` // cost of pressing every key, e.g. OK button of TV remote
+	Keys  []Key                  `json:"keys"`
+	Edges map[string][][2]string `json:"edges"`
+	// Clusters - groups of keys by name, e.g. halves of split keyboard, optional
+	Clusters map[string]Cluster `json:"clusters,omitempty"`
+}
+
+// layoutJSON is Layout without methods for encoding/json
+type layoutJSON Layout
+
+// UnmarshalJSON moves keys of clusters by origins of their clusters, in memory all coordinates are absolute
+func (l *Layout) UnmarshalJSON(data []byte) error {
+	var j layoutJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*l = Layout(j)
+	l.moveClusters(1)
+	return nil
+}
+
+// MarshalJSON writes coordinates of keys of clusters relative to origins of their clusters
+func (l Layout) MarshalJSON() ([]byte, error) {
+	l.Keys = append([]Key(nil), l.Keys...)
+	l.moveClusters(-1)
+	return json.Marshal(layoutJSON(l))
+}
+
+// moveClusters adds origins of clusters multiplied by sign to coordinates of their keys
+func (l *Layout) moveClusters(sign float64) {
+	for i, k := range l.Keys {
+		if c, ok := l.Clusters[k.Cluster]; ok && k.Cluster != "" {
+			l.Keys[i].X += sign * c.X
+			l.Keys[i].Y += sign * c.Y
+		}
+	}
+}
+
+// Width returns width of the key, 1u if not set
+func (k Key) Width() float64 {
+	if k.W == 0 {
+		return 1
+	}
+	return k.W
+}
+
+// Height returns height of the key, 1u if not set
+func (k Key) Height() float64 {
+	if k.H == 0 {
+		return 1
+	}
+	return k.H
+}
+
+// Center returns coordinates of the key center in key units
+func (k Key) Center() (float64, float64) {
+	return k.X + k.Width()/2, k.Y + k.Height()/2
+}
+
+// Row returns number of the row, rows start from 0 at the top
+func (k Key) Row() int {
+	return int(math.Round(k.Y))
+}
+
+// Properties returns geometry, row, finger and hand of the key for the graph vertex
+func (k Key) Properties() graph.VertexProperties {
+	x, y := k.Center()
+	return graph.VertexProperties{
+		X:      x,
+		Y:      y,
+		Width:  k.Width(),
+		Row:    k.Row(),
+		Finger: k.Finger,
+		Hand:   k.Hand,
+	}
+}
+
+// Distance returns distance between key centers in key units
+func (k Key) Distance(k2 Key) float64 {
+	x1, y1 := k.Center()
+	x2, y2 := k2.Center()
+	return math.Hypot(x2-x1, y2-y1)
+}
+
+func Load(filename string) (*Layout, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFile(data, filename)
+}
+
+// LoadFS loads layout from the file system, e.g. embedded into the binary
+func LoadFS(fsys fs.FS, filename string) (*Layout, error) {
+	data, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFile(data, filename)
+}
+
+// parseFile parses layout in JSON, graph in DOT format, keyboard-layout-editor data or Windows KLC file
+// by the extension of the file
+func parseFile(data []byte, filename string) (*Layout, error) {
+	var (
+		l   *Layout
+		err error
+	)
+
+	name := strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".dot":
+		l, err = parseDot(data, name)
+	case ".kle":
+		l, err = ParseKLE(data, name)
+	case ".klc":
+		l, err = ParseKLC(data, name, "")
+	default:
+		l, err = Parse(data)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("layout %s: %w", filename, err)
+	}
+	return l, nil
+}
+
+// parseDot reads graph in DOT format, its edges are used for all connectivity modes
+func parseDot(data []byte, name string) (*Layout, error) {
+	g, err := graph.ReadDot(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return FromGraph(name, g, ModeTask, ModeNormalized)
+}
+
+// FromGraph returns layout with keys placed by coordinates of vertices and edges of the graph for every mode,
+// so the graph may be changed in code or in DOT file and used as the layout.
+// The layout has no edge weights and one press cost of all keys, so other weights are an error
+func FromGraph(name string, g graph.Graph[string, graph.Vertex], modes ...string) (*Layout, error) {
+	vertices, err := g.Vertices()
+	if err != nil {
+		return nil, err
+	}
+
+	weights, err := g.VertexWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{Name: name, Edges: make(map[string][][2]string, len(modes))}
+	for i, hash := range vertices {
+		if i == 0 {
+			l.Press = weights[hash]
+		} else if weights[hash] != l.Press {
+			return nil, fmt.Errorf("%w: %q press %d, %q press %d", ErrKeyPress, vertices[0], l.Press, hash, weights[hash])
+		}
+
+		p, err := g.VertexProperties(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		k := Key{Name: hash, Finger: p.Finger, Hand: p.Hand}
+		if levels, ok := p.Attributes["levels"]; ok {
+			k.Levels = strings.Split(levels, " ")
+		}
+		if p.Width != 0 && p.Width != 1 {
+			k.W = p.Width
+		}
+		//coordinates of the vertex are the key center, keys without coordinates are left at zero
+		if p.X != 0 || p.Y != 0 {
+			k.X, k.Y = p.X-k.Width()/2, p.Y-k.Height()/2
+		}
+		l.Keys = append(l.Keys, k)
+	}
+
+	edges, err := g.Edges()
+	if err != nil {
+		return nil, err
+	}
+
+	//edges of the layout are undirected
+	var pairs [][2]string
+	seen := make(map[[2]string]bool, len(edges))
+	for _, e := range edges {
+		if e.Weight != 1 {
+			return nil, fmt.Errorf("%w: %s -- %s weight %d", ErrWeightedEdge, e.Source, e.Target, e.Weight)
+		}
+		if seen[[2]string{e.Target, e.Source}] {
+			continue
+		}
+		seen[[2]string{e.Source, e.Target}] = true
+		pairs = append(pairs, [2]string{e.Source, e.Target})
+	}
+
+	for _, mode := range modes {
+		l.Edges[mode] = pairs
+	}
+
+	if err = l.Validate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func Parse(data []byte) (*Layout, error) {
+	var l Layout
+
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, err
+	}
+
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (l *Layout) Save(filename string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0o644)
+}
+
+// Hash returns hash of the layout content, it changes with any key or edge
+func (l *Layout) Hash() (string, error) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Validate checks that keys are unique, edges connect existing keys, hands and clusters are known
+// and the press cost is not negative
+func (l *Layout) Validate() error {
+	if len(l.Keys) == 0 {
+		return ErrNoKeys
+	}
+
+	if l.Press < 0 {
+		return fmt.Errorf("press cost %d: %w", l.Press, graph.ErrNegativeWeight)
+	}
+
+	names := make(map[string]bool, len(l.Keys))
+	for _, k := range l.Keys {
+		if names[k.Name] {
+			return fmt.Errorf("%w: %q", ErrDuplicateKey, k.Name)
+		}
+		names[k.Name] = true
+
+		if k.Hand != "" && k.Hand != graph.HandLeft && k.Hand != graph.HandRight {
+			return fmt.Errorf("key %q: %w: %q", k.Name, ErrUnknownHand, k.Hand)
+		}
+
+		if _, ok := l.Clusters[k.Cluster]; k.Cluster != "" && !ok {
+			return fmt.Errorf("key %q: %w: %q", k.Name, ErrUnknownCluster, k.Cluster)
+		}
+	}
+
+	for name, c := range l.Clusters {
+		if c.Hand != "" && c.Hand != graph.HandLeft && c.Hand != graph.HandRight {
+			return fmt.Errorf("cluster %q: %w: %q", name, ErrUnknownHand, c.Hand)
+		}
+	}
+
+	for mode, edges := range l.Edges {
+		for _, e := range edges {
+			for _, name := range e {
+				if !names[name] {
+					return fmt.Errorf("edge %v of mode %s: %w: %q", e, mode, ErrUnknownKey, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Key returns key by its name
+func (l *Layout) Key(name string) (Key, bool) {
+	for _, k := range l.Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// UnitMM returns size of 1u in millimetres
+func (l *Layout) UnitMM() float64 {
+	if l.Unit == 0 {
+		return defaultUnit
+	}
+	return l.Unit
+}
+
+// Graph returns graph of the keyboard for the connectivity mode, press contains weights of vertices
+// added to the press cost of the layout
+func (l *Layout) Graph(mode string, press map[string]int) (graph.Graph[string, graph.Vertex], error) {
+	edges, ok := l.Edges[mode]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMode, mode)
+	}
+
+	for name := range press {
+		if _, ok := l.Key(name); !ok {
+			return nil, fmt.Errorf("press cost: %w: %q", ErrUnknownKey, name)
+		}
+	}
+
+	hash := func(v graph.Vertex) string {
+		return v.Name
+	}
+	g := graph.New(hash)
+
+	for _, k := range l.Keys {
+		p := k.Properties()
+		p.Hand = l.KeyHand(k)
+		if k.Cluster != "" {
+			p.Attributes = map[string]string{"cluster": k.Cluster}
+		}
+		if len(k.Levels) > 0 {
+			if p.Attributes == nil {
+				p.Attributes = make(map[string]string, 1)
+			}
+			p.Attributes["levels"] = strings.Join(k.Levels, " ")
+		}
+
+		if err := g.AddVertex(graph.Vertex{Name: k.Name, Weight: l.Press + press[k.Name]}, graph.WithVertexProperties(p)); err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Name, err)
+		}
+	}
+
+	for _, e := range edges {
+		if err := g.AddEdge(e[0], e[1]); err != nil {
+			return nil, fmt.Errorf("edge %v: %w", e, err)
+		}
+	}
+	return g, nil
+}
+
+// Exclude returns copy of the layout without broken or invisible keys and their edges, paths go around them.
+// The name gets suffix with excluded keys, so distance maps of the full layout are kept
+func (l *Layout) Exclude(keys ...string) (*Layout, error) {
+	if len(keys) == 0 {
+		return l, nil
+	}
+
+	excluded := make(map[string]bool, len(keys))
+	for _, name := range keys {
+		if _, ok := l.Key(name); !ok {
+			return nil, fmt.Errorf("excluded key: %w: %q", ErrUnknownKey, name)
+		}
+		excluded[name] = true
+	}
+
+	res := &Layout{
+		Name:     l.Name + "_without_" + strings.Join(keys, "_"),
+		Unit:     l.Unit,
+		Press:    l.Press,
+		Edges:    make(map[string][][2]string, len(l.Edges)),
+		Clusters: l.Clusters,
+	}
+
+	for _, k := range l.Keys {
+		if !excluded[k.Name] {
+			res.Keys = append(res.Keys, k)
+		}
+	}
+
+	for mode, edges := range l.Edges {
+		res.Edges[mode] = make([][2]string, 0, len(edges))
+		for _, e := range edges {
+			if !excluded[e[0]] && !excluded[e[1]] {
+				res.Edges[mode] = append(res.Edges[mode], e)
+			}
+		}
+	}
+
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Rename returns copy of the layout with keys renamed by names, e.g. letters swapped by optimize.Annealer.
+// Keys which are not in names keep their names
+func (l *Layout) Rename(names map[string]string) (*Layout, error) {
+	rename := func(name string) string {
+		if n, ok := names[name]; ok {
+			return n
+		}
+		return name
+	}
+
+	res := &Layout{
+		Name:     l.Name,
+		Unit:     l.Unit,
+		Press:    l.Press,
+		Keys:     make([]Key, len(l.Keys)),
+		Edges:    make(map[string][][2]string, len(l.Edges)),
+		Clusters: l.Clusters,
+	}
+
+	for i, k := range l.Keys {
+		k.Name = rename(k.Name)
+		res.Keys[i] = k
+	}
+
+	for mode, edges := range l.Edges {
+		res.Edges[mode] = make([][2]string, len(edges))
+		for i, e := range edges {
+			res.Edges[mode][i] = [2]string{rename(e[0]), rename(e[1])}
+		}
+	}
+
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// KeyHand returns the hand of the key or of its cluster, empty if not set
+func (l *Layout) KeyHand(k Key) string {
+	if k.Hand != "" {
+		return k.Hand
+	}
+	return l.Clusters[k.Cluster].Hand
+}
+
+// Hands returns letters typed by every hand, e.g. for processor fingers of two hands.
+// Every letter should have a hand
+func (l *Layout) Hands() (map[string]string, error) {
+	res := make(map[string]string, 2)
+
+	for _, k := range l.Keys {
+		if len(k.Name) != 1 || k.Name[0] < 'a' || k.Name[0] > 'z' {
+			continue
+		}
+
+		hand := l.KeyHand(k)
+		if hand == "" {
+			return nil, fmt.Errorf("%w: %q", ErrNoHand, k.Name)
+		}
+		res[hand] += k.Name
+	}
+	return res, nil
+}
+
+// Fingers returns letters typed by every finger in order of keys, e.g. for processor fingers.
+// Fingers of different hands are different, nil if no letter has a finger, otherwise every letter should have it
+func (l *Layout) Fingers() ([]string, error) {
+	var (
+		res     []string
+		index   = make(map[string]int)
+		missing string
+	)
+
+	for _, k := range l.Keys {
+		if len(k.Name) != 1 || k.Name[0] < 'a' || k.Name[0] > 'z' {
+			continue
+		}
+		if k.Finger == "" {
+			if missing == "" {
+				missing = k.Name
+			}
+			continue
+		}
+
+		finger := l.KeyHand(k) + " " + k.Finger
+		i, ok := index[finger]
+		if !ok {
+			i = len(res)
+			index[finger] = i
+			res = append(res, "")
+		}
+		res[i] += k.Name
+	}
+
+	if len(res) > 0 && missing != "" {
+		return nil, fmt.Errorf("%w: %q", ErrNoFinger, missing)
+	}
+	return res, nil
+}
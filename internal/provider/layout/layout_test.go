@@ -0,0 +1,308 @@
+//go:build layoutTest
+// +build layoutTest
+
+package layout
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"granny-pass/internal/provider/graph"
+)
+
+func TestLayout(t *testing.T) {
+	t.Run("test layout functions", func(t *testing.T) {
+		var (
+			l   *Layout
+			err error
+			k   Key
+			ok  bool
+			m   map[string]map[string]int
+		)
+
+		t.Run("Load", func(t *testing.T) {
+			l, err = Load("testdata/small.json")
+			assert.NoError(t, err)
+			assert.Equal(t, "small", l.Name)
+			assert.Equal(t, 4, len(l.Keys))
+
+			_, err = Load("testdata/nonexistent.json")
+			assert.Error(t, err)
+		})
+
+		t.Run("LoadFS", func(t *testing.T) {
+			l1, err := LoadFS(os.DirFS("testdata"), "small.json")
+			assert.NoError(t, err)
+			assert.Equal(t, l, l1)
+
+			_, err = LoadFS(os.DirFS("testdata"), "nonexistent.json")
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+		})
+
+		t.Run("Key", func(t *testing.T) {
+			k, ok = l.Key("z")
+			assert.Equal(t, true, ok)
+			assert.Equal(t, 2.0, k.Width())
+			assert.Equal(t, 1.0, k.Height())
+
+			x, y := k.Center()
+			assert.Equal(t, 1.5, x)
+			assert.Equal(t, 1.5, y)
+
+			_, ok = l.Key("q")
+			assert.Equal(t, false, ok)
+
+			a, _ := l.Key("a")
+			d, _ := l.Key("d")
+			assert.Equal(t, 2.0, a.Distance(d))
+			assert.Equal(t, 19.05, l.UnitMM())
+		})
+
+		t.Run("Graph", func(t *testing.T) {
+			g, err := l.Graph(ModeTask, map[string]int{"z": 2})
+			assert.NoError(t, err)
+
+			m, err = g.WFI(20)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, m["a"]["d"])
+			assert.Equal(t, 2, m["s"]["z"])
+			assert.Equal(t, 3, m["d"]["z"])
+
+			w, err := g.VertexWeights()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, w["z"])
+
+			p, err := g.VertexProperties("z")
+			assert.NoError(t, err)
+			assert.Equal(t, graph.VertexProperties{X: 1.5, Y: 1.5, Width: 2, Row: 1, Finger: "index", Hand: graph.HandLeft}, p)
+
+			p, err = g.VertexProperties("d")
+			assert.NoError(t, err)
+			assert.Equal(t, graph.VertexProperties{X: 2.5, Y: 0.5, Width: 1}, p)
+
+			g, err = l.Graph(ModeNormalized, nil)
+			assert.NoError(t, err)
+			m, err = g.WFI(20)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, m["s"]["z"])
+
+			_, err = l.Graph("other", nil)
+			assert.ErrorIs(t, err, ErrUnknownMode)
+
+			_, err = l.Graph(ModeTask, map[string]int{"q": 2})
+			assert.ErrorIs(t, err, ErrUnknownKey)
+		})
+
+		t.Run("Exclude", func(t *testing.T) {
+			e, err := l.Exclude("z")
+			assert.NoError(t, err)
+			assert.Equal(t, "small_without_z", e.Name)
+			assert.Equal(t, 3, len(e.Keys))
+			assert.Equal(t, [][2]string{{"a", "s"}, {"s", "d"}}, e.Edges[ModeNormalized])
+			//the original layout is not changed
+			assert.Equal(t, 4, len(l.Keys))
+
+			m, err = e.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, m["a"]["d"])
+
+			//d can not be reached without s
+			e, err = l.Exclude("s")
+			assert.NoError(t, err)
+			_, err = e.CostMap(CostModel{Objective: ObjectiveHops, Mode: ModeTask, MaxPathLen: 20})
+			assert.ErrorIs(t, err, graph.ErrIsolatedVertex)
+
+			_, err = l.Exclude("q")
+			assert.ErrorIs(t, err, ErrUnknownKey)
+		})
+
+		t.Run("Rename", func(t *testing.T) {
+			r, err := l.Rename(map[string]string{"a": "d", "d": "a"})
+			assert.NoError(t, err)
+			assert.Equal(t, "small", r.Name)
+			assert.Equal(t, "d", r.Keys[0].Name)
+			assert.Equal(t, [][2]string{{"d", "s"}, {"s", "a"}, {"d", "z"}}, r.Edges[ModeTask])
+			//the original layout is not changed
+			assert.Equal(t, "a", l.Keys[0].Name)
+
+			_, err = l.Rename(map[string]string{"a": "d"})
+			assert.ErrorIs(t, err, ErrDuplicateKey)
+		})
+
+		t.Run("Validate", func(t *testing.T) {
+			_, err = Parse([]byte(`{"name": "empty"}`))
+			assert.ErrorIs(t, err, ErrNoKeys)
+
+			_, err = Parse([]byte(`{"keys": [{"name": "a"}, {"name": "a", "x": 1}]}`))
+			assert.ErrorIs(t, err, ErrDuplicateKey)
+
+			_, err = Parse([]byte(`{"keys": [{"name": "a"}], "edges": {"task": [["a", "b"]]}}`))
+			assert.ErrorIs(t, err, ErrUnknownKey)
+
+			_, err = Parse([]byte(`{"keys": [{"name": "a", "hand": "middle"}]}`))
+			assert.ErrorIs(t, err, ErrUnknownHand)
+
+			_, err = Parse([]byte(`{"keys": `))
+			assert.Error(t, err)
+		})
+
+		t.Run("Save", func(t *testing.T) {
+			var l1 *Layout
+
+			filename := filepath.Join(t.TempDir(), "small.json")
+			err = l.Save(filename)
+			assert.NoError(t, err)
+
+			l1, err = Load(filename)
+			assert.NoError(t, err)
+			assert.Equal(t, l, l1)
+		})
+
+		t.Run("FromGraph", func(t *testing.T) {
+			g, err := l.Graph(ModeTask, nil)
+			assert.NoError(t, err)
+
+			l1, err := FromGraph("small", g, ModeTask)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, l.Keys, l1.Keys)
+			assert.ElementsMatch(t, l.Edges[ModeTask], l1.Edges[ModeTask])
+			assert.Equal(t, 1, len(l1.Edges))
+
+			//weights which the layout can not keep are not dropped silently
+			g, err = l.Graph(ModeTask, map[string]int{"a": 2})
+			assert.NoError(t, err)
+			_, err = FromGraph("small", g, ModeTask)
+			assert.ErrorIs(t, err, ErrKeyPress)
+
+			pressed := *l
+			pressed.Press = 3
+			g, err = pressed.Graph(ModeTask, nil)
+			assert.NoError(t, err)
+			l1, err = FromGraph("small", g, ModeTask)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, l1.Press)
+
+			assert.NoError(t, g.AddWeightedEdge("d", "z", 2))
+			_, err = FromGraph("small", g, ModeTask)
+			assert.ErrorIs(t, err, ErrWeightedEdge)
+		})
+
+		t.Run("Load DOT", func(t *testing.T) {
+			l1, err := Load("testdata/small.dot")
+			assert.NoError(t, err)
+			assert.Equal(t, "small", l1.Name)
+			assert.ElementsMatch(t, l.Keys, l1.Keys)
+
+			for _, mode := range []string{ModeTask, ModeNormalized} {
+				g, err := l1.Graph(mode, nil)
+				assert.NoError(t, err)
+				m, err = g.WFI(20)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, m["a"]["d"])
+				assert.Equal(t, 2, m["s"]["z"])
+			}
+		})
+
+		t.Run("Clusters", func(t *testing.T) {
+			sl, err := Load("testdata/split.json")
+			assert.NoError(t, err)
+
+			//coordinates are relative to the cluster origin
+			k, _ = sl.Key("k")
+			assert.Equal(t, 4.0, k.X)
+			assert.Equal(t, graph.HandRight, sl.KeyHand(k))
+
+			hands, err := sl.Hands()
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{graph.HandLeft: "as", graph.HandRight: "kl"}, hands)
+
+			g, err := sl.Graph(ModeTask, nil)
+			assert.NoError(t, err)
+			p, err := g.VertexProperties("l")
+			assert.NoError(t, err)
+			assert.Equal(t, graph.HandRight, p.Hand)
+			assert.Equal(t, map[string]string{"cluster": "right"}, p.Attributes)
+
+			filename := filepath.Join(t.TempDir(), "split.json")
+			err = sl.Save(filename)
+			assert.NoError(t, err)
+			saved, err := Load(filename)
+			assert.NoError(t, err)
+			assert.Equal(t, sl, saved)
+
+			_, err = l.Hands()
+			assert.ErrorIs(t, err, ErrNoHand)
+
+			//fingers of different hands are different even with the same name
+			fingers, err := sl.Fingers()
+			assert.NoError(t, err)
+			assert.Nil(t, fingers)
+
+			typed := *sl
+			typed.Keys = append([]Key(nil), sl.Keys...)
+			for i := range typed.Keys {
+				typed.Keys[i].Finger = "index"
+			}
+			typed.Keys[0].Finger = "middle"
+			fingers, err = typed.Fingers()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "s", "kl"}, fingers)
+
+			typed.Keys[1].Finger = ""
+			_, err = typed.Fingers()
+			assert.ErrorIs(t, err, ErrNoFinger)
+
+			wrong := *sl
+			wrong.Keys = append([]Key{{Name: "x", Cluster: "thumb"}}, sl.Keys...)
+			assert.ErrorIs(t, wrong.Validate(), ErrUnknownCluster)
+		})
+
+		t.Run("Hash", func(t *testing.T) {
+			var h1, h2 string
+
+			l1 := *l
+			h1, err = l1.Hash()
+			assert.NoError(t, err)
+			assert.Equal(t, 64, len(h1))
+
+			h2, err = l.Hash()
+			assert.NoError(t, err)
+			assert.Equal(t, h1, h2)
+
+			l1.Keys = append([]Key{}, l.Keys...)
+			l1.Keys[0].X += 0.25
+			h2, err = l1.Hash()
+			assert.NoError(t, err)
+			assert.NotEqual(t, h1, h2)
+		})
+
+		t.Run("bundled qwerty", func(t *testing.T) {
+			l, err = Load("../../../layouts/qwerty.json")
+			assert.NoError(t, err)
+
+			for _, mode := range []string{ModeTask, ModeNormalized} {
+				g, err := l.Graph(mode, nil)
+				assert.NoError(t, err)
+				m, err = g.WFI(20)
+				assert.NoError(t, err)
+
+				sp, err := g.ShortestPaths(20)
+				assert.NoError(t, err)
+				assert.Equal(t, m, sp)
+
+				// from the task: F -> H two moves, A -> E three moves
+				assert.Equal(t, 2, m["f"]["h"])
+				if mode == ModeTask {
+					assert.Equal(t, 3, m["a"]["e"])
+				}
+
+				dist := graph.BigramDistanceArray(m)
+				assert.Equal(t, 32*32, len(dist))
+			}
+		})
+	})
+}
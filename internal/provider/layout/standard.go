@@ -0,0 +1,185 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	// GeometryANSI - standard US keyboard with the long left shift and the backslash above enter
+	GeometryANSI = "ansi"
+	// GeometryISO - standard European keyboard with the extra key at the left shift and the backslash at enter
+	GeometryISO = "iso"
+
+	// edgeTolerance - keys closer than this gap in key units are neighbours
+	edgeTolerance = 0.25
+	// maxLevels - the base character and characters typed with Shift, AltGr and Shift+AltGr
+	maxLevels = 4
+)
+
+var ErrUnknownGeometry = errors.New("unknown geometry")
+
+// standardKey is a character key of the standard keyboard with its XKB key code and Windows scan code
+type standardKey struct {
+	code string
+	scan int
+	x, y float64
+	w    float64
+	iso  bool // the position is used only by ISO geometry, the key is elsewhere or absent on ANSI
+	ansi bool // the position is used only by ANSI geometry
+}
+
+// standardKeys returns character keys of the standard keyboard: digits, letters and punctuation
+// without space and modifiers, coordinates are in key units from the left top corner of the number row
+func standardKeys() []standardKey {
+	res := []standardKey{{code: "TLDE", scan: 0x29}}
+	for i := 1; i <= 12; i++ {
+		res = append(res, standardKey{code: fmt.Sprintf("AE%02d", i), scan: 0x01 + i, x: float64(i)})
+	}
+	for i := 1; i <= 12; i++ {
+		res = append(res, standardKey{code: fmt.Sprintf("AD%02d", i), scan: 0x0f + i, x: 0.5 + float64(i), y: 1})
+	}
+	res = append(res, standardKey{code: "BKSL", scan: 0x2b, x: 13.5, y: 1, w: 1.5, ansi: true})
+	for i := 1; i <= 11; i++ {
+		res = append(res, standardKey{code: fmt.Sprintf("AC%02d", i), scan: 0x1d + i, x: 0.75 + float64(i), y: 2})
+	}
+	res = append(res,
+		standardKey{code: "BKSL", scan: 0x2b, x: 12.75, y: 2, iso: true},
+		standardKey{code: "LSGT", scan: 0x56, x: 1.25, y: 3, iso: true},
+	)
+	for i := 1; i <= 10; i++ {
+		res = append(res, standardKey{code: fmt.Sprintf("AB%02d", i), scan: 0x2b + i, x: 1.25 + float64(i), y: 3})
+	}
+	return res
+}
+
+// standardLayout places characters on keys of the standard geometry, chars are levels of keys by XKB key code:
+// the base character names the key, others are typed with Shift, AltGr and Shift+AltGr, higher levels are dropped.
+// Empty geometry is ISO if the extra ISO key has characters and ANSI otherwise.
+// Keys without the base character are skipped, edges are derived from geometry
+func standardLayout(name, geometry string, chars map[string][]string) (*Layout, error) {
+	if geometry == "" {
+		geometry = GeometryANSI
+		if len(chars["LSGT"]) > 0 && chars["LSGT"][0] != "" {
+			geometry = GeometryISO
+		}
+	}
+	if geometry != GeometryANSI && geometry != GeometryISO {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGeometry, geometry)
+	}
+
+	l := &Layout{Name: name, Edges: make(map[string][][2]string, 2)}
+	used := make(map[string]int)
+
+	for _, sk := range standardKeys() {
+		if (sk.iso && geometry != GeometryISO) || (sk.ansi && geometry != GeometryANSI) {
+			continue
+		}
+
+		levels := chars[sk.code]
+		if len(levels) == 0 || levels[0] == "" {
+			continue
+		}
+		if len(levels) > maxLevels {
+			levels = levels[:maxLevels]
+		}
+
+		k := Key{Name: uniqueName(levels[0], used), X: sk.x, Y: sk.y, W: sk.w}
+		//trailing empty levels are dropped
+		for n := len(levels); n > 1; n-- {
+			if levels[n-1] != "" {
+				k.Levels = append([]string(nil), levels[1:n]...)
+				break
+			}
+		}
+		l.Keys = append(l.Keys, k)
+	}
+
+	l.Edges[ModeTask], l.Edges[ModeNormalized] = geometryEdges(l.Keys)
+
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// geometryEdges derives edges from geometry of keys: in ModeTask the key is connected to its neighbours
+// in the row and to the key below with the largest overlap, in ModeNormalized to all keys below touching it
+func geometryEdges(keys []Key) (task, normalized [][2]string) {
+	type pair struct{ a, b int }
+	seen := make(map[pair]bool)
+	add := func(edges [][2]string, i, j int) [][2]string {
+		return append(edges, [2]string{keys[i].Name, keys[j].Name})
+	}
+
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ka, kb := keys[order[a]], keys[order[b]]
+		if ka.Y != kb.Y {
+			return ka.Y < kb.Y
+		}
+		return ka.X < kb.X
+	})
+
+	for _, i := range order {
+		k := keys[i]
+		best, bestOverlap := -1, 0.0
+
+		for _, j := range order {
+			if i == j {
+				continue
+			}
+			k2 := keys[j]
+
+			//the neighbour on the right
+			if overlap(k.Y, k.Height(), k2.Y, k2.Height()) >= math.Min(k.Height(), k2.Height())/2 {
+				if gap := k2.X - (k.X + k.Width()); gap >= -edgeTolerance && gap <= edgeTolerance && k2.X > k.X {
+					if !seen[pair{i, j}] {
+						seen[pair{i, j}] = true
+						task = add(task, i, j)
+						normalized = add(normalized, i, j)
+					}
+				}
+				continue
+			}
+
+			//keys below
+			if gap := k2.Y - (k.Y + k.Height()); gap >= -edgeTolerance && gap <= edgeTolerance {
+				if o := overlap(k.X, k.Width(), k2.X, k2.Width()); o > 0 {
+					if !seen[pair{i, j}] {
+						seen[pair{i, j}] = true
+						normalized = add(normalized, i, j)
+					}
+					if o > bestOverlap {
+						best, bestOverlap = j, o
+					}
+				}
+			}
+		}
+
+		if best >= 0 {
+			task = add(task, i, best)
+		}
+	}
+	return task, normalized
+}
+
+// overlap returns length of the common part of segments [a, a+la] and [b, b+lb]
+func overlap(a, la, b, lb float64) float64 {
+	return math.Min(a+la, b+lb) - math.Max(a, b)
+}
+
+// uniqueName returns the name with a number suffix if it is used already, e.g. shift and shift_2
+func uniqueName(name string, used map[string]int) string {
+	used[name]++
+	if used[name] > 1 {
+		name += "_" + strconv.Itoa(used[name])
+	}
+	return name
+}
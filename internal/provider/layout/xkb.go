@@ -0,0 +1,385 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// xkbMaxDepth limits nesting of included files
+const xkbMaxDepth = 16
+
+var (
+	ErrXKBSyntax  = errors.New("wrong XKB symbols")
+	ErrXKBVariant = errors.New("unknown XKB variant")
+	ErrXKBInclude = errors.New("wrong XKB include")
+)
+
+var (
+	xkbBlockRe      = regexp.MustCompile(`xkb_symbols\s*"([^"]*)"\s*\{`)
+	xkbIncludeRe    = regexp.MustCompile(`(?s)^(include|augment|override|replace)\s+"([^"]*)"$`)
+	xkbIncludeEndRe = regexp.MustCompile(`\b((?:include|augment|override|replace)\s+"[^"]*")`)
+	xkbKeyRe        = regexp.MustCompile(`(?s)^(?:(replace|override|augment)\s+)?key\s*<(\w+)>\s*\{(.*)\}$`)
+	xkbGroupRe      = regexp.MustCompile(`(\w+)\s*\[\s*[Gg]roup(\d)\s*\]`)
+	xkbSymbolsRe    = regexp.MustCompile(`symbols\s*=\s*\[([^\]]*)\]`)
+	xkbLevelsRe     = regexp.MustCompile(`(^|[^=\s])\s*\[([^\]]*)\]`)
+	xkbUnicodeRe    = regexp.MustCompile(`^U([0-9A-Fa-f]{4,6})$`)
+)
+
+// xkbBlock is the xkb_symbols section of the file
+type xkbBlock struct {
+	name      string
+	isDefault bool
+	body      string
+}
+
+// xkbMerge is the way new levels of the key are merged with the included ones
+type xkbMerge int
+
+const (
+	xkbOverride xkbMerge = iota
+	xkbAugment
+	xkbReplace
+)
+
+// LoadXKB loads the variant of XKB symbols file, e.g. "de(nodeadkeys)" or "fr", from the directory
+// of symbols files, usually /usr/share/X11/xkb/symbols, the default variant is used if it is not set.
+// Included files are loaded from the same directory. Characters of the first group are placed
+// on keys of the standard geometry, levels 2-4 are Shift, AltGr and Shift+AltGr.
+// The layout is named by the file and the variant, e.g. de_nodeadkeys
+func LoadXKB(fsys fs.FS, spec, geometry string) (*Layout, error) {
+	chars := make(map[string][]string)
+	if err := loadXKB(fsys, spec, chars, xkbOverride, 0); err != nil {
+		return nil, fmt.Errorf("xkb %s: %w", spec, err)
+	}
+
+	file, variant := splitXKBSpec(spec)
+	name := file
+	if variant != "" {
+		name += "_" + variant
+	}
+
+	l, err := standardLayout(name, geometry, chars)
+	if err != nil {
+		return nil, fmt.Errorf("xkb %s: %w", spec, err)
+	}
+	return l, nil
+}
+
+// loadXKB merges keys of the variant and its included files into chars
+func loadXKB(fsys fs.FS, spec string, chars map[string][]string, merge xkbMerge, depth int) error {
+	if depth > xkbMaxDepth {
+		return fmt.Errorf("%w: too deep at %q", ErrXKBInclude, spec)
+	}
+
+	file, variant := splitXKBSpec(spec)
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrXKBInclude, err)
+	}
+
+	block, err := xkbVariant(xkbBlocks(stripXKBComments(string(data))), variant)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	for _, s := range xkbStatements(block.body) {
+		if m := xkbIncludeRe.FindStringSubmatch(s); m != nil {
+			if err = includeXKB(fsys, m[2], chars, xkbMergeMode(m[1], merge), depth); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if m := xkbKeyRe.FindStringSubmatch(s); m != nil {
+			levels, ok := xkbKeyLevels(m[3])
+			if ok {
+				mergeLevels(chars, m[2], levels, xkbMergeMode(m[1], merge))
+			}
+		}
+	}
+	return nil
+}
+
+// includeXKB loads files of the include statement, e.g. "latin(type4)+level3(ralt_switch)",
+// files after | augment the keys, parts of other groups, e.g. "us:2", are skipped
+func includeXKB(fsys fs.FS, include string, chars map[string][]string, merge xkbMerge, depth int) error {
+	for include != "" {
+		partMerge := merge
+		switch include[0] {
+		case '+':
+			include = include[1:]
+		case '|':
+			include, partMerge = include[1:], xkbAugment
+		}
+
+		n := strings.IndexAny(include, "+|")
+		if n < 0 {
+			n = len(include)
+		}
+		part := include[:n]
+		include = include[n:]
+
+		if i := strings.IndexByte(part, ':'); i >= 0 {
+			if part[i+1:] != "1" {
+				continue
+			}
+			part = part[:i]
+		}
+
+		if err := loadXKB(fsys, part, chars, partMerge, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func xkbMergeMode(word string, inherited xkbMerge) xkbMerge {
+	switch word {
+	case "augment":
+		return xkbAugment
+	case "replace":
+		return xkbReplace
+	case "override":
+		return xkbOverride
+	}
+	return inherited
+}
+
+// mergeLevels sets levels of the key, empty levels (NoSymbol) keep included characters
+func mergeLevels(chars map[string][]string, code string, levels []string, merge xkbMerge) {
+	old := chars[code]
+	if merge == xkbReplace {
+		old = nil
+	}
+
+	res := make([]string, len(old))
+	copy(res, old)
+	for i, c := range levels {
+		if i >= len(res) {
+			res = append(res, "")
+		}
+		if c == "" || (merge == xkbAugment && res[i] != "") {
+			continue
+		}
+		res[i] = c
+	}
+	chars[code] = res
+}
+
+// splitXKBSpec splits "file(variant)" into the file and the variant
+func splitXKBSpec(spec string) (string, string) {
+	spec = strings.TrimSpace(spec)
+	if n := strings.IndexByte(spec, '('); n >= 0 && strings.HasSuffix(spec, ")") {
+		return spec[:n], spec[n+1 : len(spec)-1]
+	}
+	return spec, ""
+}
+
+func stripXKBComments(data string) string {
+	var (
+		sb       strings.Builder
+		inString bool
+	)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c == '"' {
+			inString = !inString
+		}
+		if !inString && (c == '/' && i+1 < len(data) && data[i+1] == '/' || c == '#') {
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				sb.WriteByte('\n')
+			}
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// xkbBlocks returns xkb_symbols sections of the file, flags before the section mark the default one
+func xkbBlocks(data string) []xkbBlock {
+	var (
+		res  []xkbBlock
+		prev int
+	)
+
+	for _, m := range xkbBlockRe.FindAllStringSubmatchIndex(data, -1) {
+		if m[0] < prev {
+			continue
+		}
+
+		end := m[1]
+		for depth := 1; end < len(data) && depth > 0; end++ {
+			switch data[end] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+
+		res = append(res, xkbBlock{
+			name:      data[m[2]:m[3]],
+			isDefault: strings.Contains(" "+strings.Join(strings.Fields(data[prev:m[0]]), " ")+" ", " default "),
+			body:      data[m[1] : end-1],
+		})
+		prev = end
+	}
+	return res
+}
+
+// xkbVariant returns the block of the variant, the default or the first one if the variant is empty
+func xkbVariant(blocks []xkbBlock, variant string) (xkbBlock, error) {
+	if len(blocks) == 0 {
+		return xkbBlock{}, fmt.Errorf("%w: no xkb_symbols", ErrXKBSyntax)
+	}
+
+	for _, b := range blocks {
+		if (variant == "" && b.isDefault) || (variant != "" && b.name == variant) {
+			return b, nil
+		}
+	}
+	if variant == "" {
+		return blocks[0], nil
+	}
+	return xkbBlock{}, fmt.Errorf("%w: %q", ErrXKBVariant, variant)
+}
+
+// xkbStatements splits the body of the section by semicolons outside of braces, brackets and strings,
+// include statements have no semicolon
+func xkbStatements(body string) []string {
+	body = xkbIncludeEndRe.ReplaceAllString(body, "$1;")
+
+	var (
+		res      []string
+		depth    int
+		inString bool
+		start    int
+	)
+
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			depth--
+		case c == ';' && depth == 0:
+			if s := strings.TrimSpace(body[start:i]); s != "" {
+				res = append(res, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(body[start:]); s != "" {
+		res = append(res, s)
+	}
+	return res
+}
+
+// xkbKeyLevels returns characters of the first group of the key definition,
+// e.g. `[ q, Q, at ]` or `type= "FOUR_LEVEL", symbols[Group1]= [ q, Q ]`
+func xkbKeyLevels(def string) ([]string, bool) {
+	//Group1 is kept as the plain name, other groups are removed with their values
+	def = xkbGroupRe.ReplaceAllStringFunc(def, func(s string) string {
+		m := xkbGroupRe.FindStringSubmatch(s)
+		if m[2] == "1" {
+			return m[1]
+		}
+		return m[1] + "_group" + m[2]
+	})
+
+	var list string
+	if m := xkbSymbolsRe.FindStringSubmatch(def); m != nil {
+		list = m[1]
+	} else if m = xkbLevelsRe.FindStringSubmatch(def); m != nil {
+		list = m[2]
+	} else {
+		return nil, false
+	}
+
+	var res []string
+	for _, sym := range strings.Split(list, ",") {
+		res = append(res, keysymChar(strings.TrimSpace(sym)))
+	}
+	return res, true
+}
+
+// keysymChar returns the character of the keysym, e.g. "at" -> "@", "U0439" -> "й".
+// Keysyms without characters, e.g. dead_acute, are returned as they are, NoSymbol is empty
+func keysymChar(sym string) string {
+	switch {
+	case sym == "" || sym == "NoSymbol" || sym == "VoidSymbol":
+		return ""
+	case len([]rune(sym)) == 1:
+		return sym
+	}
+
+	if m := xkbUnicodeRe.FindStringSubmatch(sym); m != nil {
+		n, _ := strconv.ParseUint(m[1], 16, 32)
+		return string(rune(n))
+	}
+
+	if strings.HasPrefix(sym, "0x") {
+		if n, err := strconv.ParseUint(sym[2:], 16, 32); err == nil {
+			if n >= 0x1000000 {
+				n -= 0x1000000
+			}
+			if n <= unicode.MaxRune {
+				return string(rune(n))
+			}
+		}
+	}
+
+	if c, ok := keysyms[sym]; ok {
+		return c
+	}
+	return sym
+}
+
+// keysyms maps names of keysyms to characters: ASCII punctuation, Latin-1 and Cyrillic letters
+var keysyms = func() map[string]string {
+	res := map[string]string{"EuroSign": "€", "quoteright": "'", "quoteleft": "`", "numerosign": "№"}
+
+	add := func(first rune, names string) {
+		for i, name := range strings.Fields(names) {
+			res[name] = string(first + rune(i))
+		}
+	}
+	add(0x20, "space exclam quotedbl numbersign dollar percent ampersand apostrophe parenleft parenright "+
+		"asterisk plus comma minus period slash")
+	add(0x3a, "colon semicolon less equal greater question at")
+	add(0x5b, "bracketleft backslash bracketright asciicircum underscore grave")
+	add(0x7b, "braceleft bar braceright asciitilde")
+	add(0xa0, "nobreakspace exclamdown cent sterling currency yen brokenbar section diaeresis copyright "+
+		"ordfeminine guillemotleft notsign hyphen registered macron degree plusminus twosuperior threesuperior "+
+		"acute mu paragraph periodcentered cedilla onesuperior masculine guillemotright onequarter onehalf "+
+		"threequarters questiondown")
+	add(0xc0, "Agrave Aacute Acircumflex Atilde Adiaeresis Aring AE Ccedilla Egrave Eacute Ecircumflex "+
+		"Ediaeresis Igrave Iacute Icircumflex Idiaeresis ETH Ntilde Ograve Oacute Ocircumflex Otilde Odiaeresis "+
+		"multiply Oslash Ugrave Uacute Ucircumflex Udiaeresis Yacute THORN ssharp")
+	add(0xe0, "agrave aacute acircumflex atilde adiaeresis aring ae ccedilla egrave eacute ecircumflex "+
+		"ediaeresis igrave iacute icircumflex idiaeresis eth ntilde ograve oacute ocircumflex otilde odiaeresis "+
+		"division oslash ugrave uacute ucircumflex udiaeresis yacute thorn ydiaeresis")
+
+	//Cyrillic_a .. Cyrillic_ya and capital letters with upper case names, e.g. Cyrillic_YA
+	cyrillic := "a be ve ghe de ie zhe ze i shorti ka el em en o pe er es te u ef ha tse che sha shcha " +
+		"hardsign yeru softsign e yu ya"
+	for i, name := range strings.Fields(cyrillic) {
+		res["Cyrillic_"+name] = string('а' + rune(i))
+		res["Cyrillic_"+strings.ToUpper(name)] = string('А' + rune(i))
+	}
+	res["Cyrillic_io"], res["Cyrillic_IO"] = "ё", "Ё"
+	return res
+}()
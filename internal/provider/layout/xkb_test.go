@@ -0,0 +1,106 @@
+//go:build layoutTest
+// +build layoutTest
+
+package layout
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXKB(t *testing.T) {
+	t.Run("test XKB import functions", func(t *testing.T) {
+		var (
+			symbols = os.DirFS("testdata/xkb")
+			l       *Layout
+			k       Key
+			err     error
+		)
+
+		t.Run("LoadXKB default variant", func(t *testing.T) {
+			l, err = LoadXKB(symbols, "small", "")
+			assert.NoError(t, err)
+			assert.Equal(t, "small", l.Name)
+			assert.Equal(t, 10, len(l.Keys))
+
+			//keys of the included file
+			k, _ = l.Key("q")
+			assert.Equal(t, Key{Name: "q", X: 1.5, Y: 1, Levels: []string{"Q", "@"}}, k)
+
+			//NoSymbol keeps included characters
+			k, _ = l.Key("e")
+			assert.Equal(t, []string{"E", "€"}, k.Levels)
+
+			//explicit group, the second group is skipped
+			k, _ = l.Key("s")
+			assert.Equal(t, []string{"S", "ß", "ẞ"}, k.Levels)
+			k, _ = l.Key("d")
+			assert.Equal(t, []string{"D"}, k.Levels)
+		})
+
+		t.Run("edges from geometry", func(t *testing.T) {
+			task := l.Edges[ModeTask]
+			assert.Contains(t, task, [2]string{"q", "w"})
+			assert.Contains(t, task, [2]string{"1", "q"})
+			assert.Contains(t, task, [2]string{"q", "a"})
+			assert.NotContains(t, task, [2]string{"w", "a"})
+
+			assert.Contains(t, l.Edges[ModeNormalized], [2]string{"w", "a"})
+		})
+
+		t.Run("variant overrides included keys", func(t *testing.T) {
+			l, err = LoadXKB(symbols, "small(fr)", "")
+			assert.NoError(t, err)
+			assert.Equal(t, "small_fr", l.Name)
+
+			k, _ = l.Key("a")
+			assert.Equal(t, Key{Name: "a", X: 1.5, Y: 1, Levels: []string{"A", "æ", "Æ"}}, k)
+
+			//the extra key selects ISO geometry
+			k, _ = l.Key("<")
+			assert.Equal(t, Key{Name: "<", X: 1.25, Y: 3, Levels: []string{">"}}, k)
+			assert.Contains(t, l.Edges[ModeTask], [2]string{"<", "w"})
+
+			_, err = LoadXKB(symbols, "small(fr)", GeometryANSI)
+			assert.NoError(t, err)
+			_, err = LoadXKB(symbols, "small(fr)", "jis")
+			assert.ErrorIs(t, err, ErrUnknownGeometry)
+		})
+
+		t.Run("levels in graph", func(t *testing.T) {
+			g, err := l.Graph(ModeTask, nil)
+			assert.NoError(t, err)
+
+			p, err := g.VertexProperties("a")
+			assert.NoError(t, err)
+			assert.Equal(t, "A æ Æ", p.Attributes["levels"])
+		})
+
+		t.Run("wrong files", func(t *testing.T) {
+			_, err = LoadXKB(symbols, "small(none)", "")
+			assert.ErrorIs(t, err, ErrXKBVariant)
+
+			_, err = LoadXKB(symbols, "small(loop)", "")
+			assert.ErrorIs(t, err, ErrXKBInclude)
+
+			_, err = LoadXKB(symbols, "none", "")
+			assert.ErrorIs(t, err, ErrXKBInclude)
+		})
+
+		t.Run("keysymChar", func(t *testing.T) {
+			assert.Equal(t, "q", keysymChar("q"))
+			assert.Equal(t, "#", keysymChar("numbersign"))
+			assert.Equal(t, "ü", keysymChar("udiaeresis"))
+			assert.Equal(t, "Ä", keysymChar("Adiaeresis"))
+			assert.Equal(t, "й", keysymChar("Cyrillic_shorti"))
+			assert.Equal(t, "Я", keysymChar("Cyrillic_YA"))
+			assert.Equal(t, "ё", keysymChar("Cyrillic_io"))
+			assert.Equal(t, "ẞ", keysymChar("U1E9E"))
+			assert.Equal(t, "ẞ", keysymChar("0x1001E9E"))
+			assert.Equal(t, "dead_acute", keysymChar("dead_acute"))
+			assert.Equal(t, "", keysymChar("NoSymbol"))
+		})
+	})
+}
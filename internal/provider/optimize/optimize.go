@@ -0,0 +1,292 @@
+package optimize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultIterations - count of tried swaps of keys
+	DefaultIterations = 200000
+	// DefaultSeed makes results reproducible
+	DefaultSeed = 1
+
+	// temperatureSamples - count of random swaps used to choose the initial temperature
+	temperatureSamples = 100
+	// finalTemperature - part of the initial temperature at the end of annealing
+	finalTemperature = 0.001
+)
+
+var (
+	ErrNoKeys       = errors.New("less than two keys to place")
+	ErrUnknownKey   = errors.New("unknown key")
+	ErrNoTexts      = errors.New("no texts to type")
+	ErrWrongOptions = errors.New("wrong optimizer options")
+)
+
+// Result is the arrangement of keys found by the optimizer
+type Result struct {
+	// Placement - the key placed at the position of every moved key of the initial layout
+	Placement map[string]string
+	// Cost - total path length of all texts
+	Cost int
+	// Initial - total path length of all texts on the initial layout
+	Initial int
+	// Texts - count of texts, the average path length is Cost/Texts
+	Texts int
+}
+
+// Average returns average path length of the text
+func (r Result) Average() float64 {
+	if r.Texts == 0 {
+		return 0
+	}
+	return float64(r.Cost) / float64(r.Texts)
+}
+
+// Annealer searches placement of keys with the shortest path for the texts by simulated annealing:
+// random swaps of two keys are taken if they shorten the path or, with probability decreasing
+// during the search, if they make it longer. Positions of keys and costs of moves between them
+// do not change with swaps, so the distance map is calculated once, e.g. by layout.CostMap
+type Annealer struct {
+	// Iterations - count of tried swaps
+	Iterations int
+	// Temperature - initial temperature in units of the cost, chosen by random swaps if 0,
+	// only swaps shortening the path are taken if random swaps do not make it longer
+	Temperature float64
+	// Seed of the random generator
+	Seed int64
+	// Skipped - count of texts with characters which are not keys of the layout
+	Skipped int
+
+	keys    []string
+	moved   []int   // indexes of keys which can be swapped, other keys stay at their positions
+	dist    [][]int // cost of moves between initial positions of keys
+	weights [][]int // count of bigrams of texts by indexes of keys
+	texts   int
+}
+
+// NewAnnealer returns optimizer of placement of the moved keys, dist contains costs of moves between keys
+// of the initial layout. Other characters of dist stay at their positions, but bigrams with them are typed too.
+// Texts are words of the vocabulary or passphrases, spaces are not typed.
+// Texts with characters which are not keys of dist are skipped
+func NewAnnealer(dist map[string]map[string]int, moved []string, texts []string) (*Annealer, error) {
+	if len(moved) < 2 {
+		return nil, fmt.Errorf("%w: %d", ErrNoKeys, len(moved))
+	}
+
+	//keys named by a character can be typed, the order is fixed for the random generator
+	var keys []string
+	for k := range dist {
+		if len([]rune(k)) == 1 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	a := &Annealer{
+		Iterations: DefaultIterations,
+		Seed:       DefaultSeed,
+		keys:       keys,
+		dist:       make([][]int, len(keys)),
+		weights:    make([][]int, len(keys)),
+	}
+
+	index := make(map[rune]int, len(keys))
+	for i, k := range keys {
+		index[[]rune(k)[0]] = i
+
+		a.dist[i] = make([]int, len(keys))
+		a.weights[i] = make([]int, len(keys))
+		for j, k2 := range keys {
+			d, ok := dist[k][k2]
+			if !ok && i != j {
+				return nil, fmt.Errorf("%w: no distance %q -> %q", ErrUnknownKey, k, k2)
+			}
+			a.dist[i][j] = d
+		}
+	}
+
+	seen := make(map[int]bool, len(moved))
+	for _, k := range moved {
+		r := []rune(k)
+		if len(r) != 1 {
+			return nil, fmt.Errorf("%w: %q is not a character", ErrUnknownKey, k)
+		}
+		i, ok := index[r[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKey, k)
+		}
+		if !seen[i] {
+			seen[i] = true
+			a.moved = append(a.moved, i)
+		}
+	}
+	if len(a.moved) < 2 {
+		return nil, fmt.Errorf("%w: %d", ErrNoKeys, len(a.moved))
+	}
+
+	for _, text := range texts {
+		var idx []int
+		for _, r := range strings.ReplaceAll(text, " ", "") {
+			i, ok := index[r]
+			if !ok {
+				idx = nil
+				break
+			}
+			idx = append(idx, i)
+		}
+		if len(idx) == 0 {
+			a.Skipped++
+			continue
+		}
+
+		a.texts++
+		for i := 1; i < len(idx); i++ {
+			a.weights[idx[i-1]][idx[i]]++
+		}
+	}
+
+	if a.texts == 0 {
+		return nil, fmt.Errorf("%w: %d skipped", ErrNoTexts, a.Skipped)
+	}
+	return a, nil
+}
+
+// Run searches the placement starting from the initial layout and returns the best found one
+func (a *Annealer) Run() (Result, error) {
+	if a.Iterations < 0 || a.Temperature < 0 {
+		return Result{}, fmt.Errorf("%w: iterations %d, temperature %g", ErrWrongOptions, a.Iterations, a.Temperature)
+	}
+
+	rnd := rand.New(rand.NewSource(a.Seed))
+	n := len(a.keys)
+
+	//pos[key] - index of the initial position of the key
+	pos := make([]int, n)
+	for i := range pos {
+		pos[i] = i
+	}
+	cost := a.cost(pos)
+
+	best := append([]int(nil), pos...)
+	bestCost := cost
+	res := Result{Initial: cost, Texts: a.texts}
+
+	t0 := a.Temperature
+	if t0 == 0 {
+		t0 = a.temperature(pos, rnd)
+	}
+
+	for it := 0; it < a.Iterations; it++ {
+		t := t0 * math.Pow(finalTemperature, float64(it)/float64(a.Iterations))
+
+		i, j := a.pair(rnd)
+		d := a.delta(pos, i, j)
+		if d > 0 && rnd.Float64() >= math.Exp(-float64(d)/t) {
+			continue
+		}
+
+		pos[i], pos[j] = pos[j], pos[i]
+		cost += d
+		if cost < bestCost {
+			bestCost = cost
+			copy(best, pos)
+		}
+	}
+
+	res.Cost = bestCost
+	res.Placement = make(map[string]string, len(a.moved))
+	for _, k := range a.moved {
+		res.Placement[a.keys[best[k]]] = a.keys[k]
+	}
+	return res, nil
+}
+
+// pair returns two different random moved keys
+func (a *Annealer) pair(rnd *rand.Rand) (int, int) {
+	i, j := rnd.Intn(len(a.moved)), rnd.Intn(len(a.moved)-1)
+	if j >= i {
+		j++
+	}
+	return a.moved[i], a.moved[j]
+}
+
+// Cost returns total path length of texts for the placement: the key placed at the position of the key,
+// keys which are not in the placement stay at their positions
+func (a *Annealer) Cost(placement map[string]string) (int, error) {
+	index := make(map[string]int, len(a.keys))
+	for i, k := range a.keys {
+		index[k] = i
+	}
+
+	pos := make([]int, len(a.keys))
+	for i := range pos {
+		pos[i] = -1
+	}
+	for i, k := range a.keys {
+		placed, ok := placement[k]
+		if !ok {
+			placed = k
+		}
+		j, ok := index[placed]
+		if !ok || pos[j] >= 0 {
+			return 0, fmt.Errorf("%w: %q placed at %q", ErrUnknownKey, placed, k)
+		}
+		pos[j] = i
+	}
+	return a.cost(pos), nil
+}
+
+// cost returns total path length of texts, pos contains the position of every key
+func (a *Annealer) cost(pos []int) int {
+	sum := 0
+	for i, row := range a.weights {
+		for j, w := range row {
+			sum += w * a.dist[pos[i]][pos[j]]
+		}
+	}
+	return sum
+}
+
+// delta returns the change of the cost after the swap of keys i and j
+func (a *Annealer) delta(pos []int, i, j int) int {
+	var (
+		pi, pj = pos[i], pos[j]
+		d      = a.dist
+		w      = a.weights
+		res    int
+	)
+
+	for k, pk := range pos {
+		if k == i || k == j {
+			continue
+		}
+		res += w[i][k]*(d[pj][pk]-d[pi][pk]) + w[k][i]*(d[pk][pj]-d[pk][pi])
+		res += w[j][k]*(d[pi][pk]-d[pj][pk]) + w[k][j]*(d[pk][pi]-d[pk][pj])
+	}
+	res += (w[i][j] - w[j][i]) * (d[pj][pi] - d[pi][pj])
+	//repeated keys, the cost of pressing the key again may be not zero
+	return res + (w[i][i]-w[j][j])*(d[pj][pj]-d[pi][pi])
+}
+
+// temperature returns the mean growth of the cost by random swaps, so at first most of bad swaps are taken
+func (a *Annealer) temperature(pos []int, rnd *rand.Rand) float64 {
+	var sum, cnt int
+	for s := 0; s < temperatureSamples; s++ {
+		i, j := a.pair(rnd)
+		if d := a.delta(pos, i, j); d > 0 {
+			sum += d
+			cnt++
+		}
+	}
+
+	if cnt == 0 {
+		return 0
+	}
+	return float64(sum) / float64(cnt)
+}
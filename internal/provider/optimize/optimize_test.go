@@ -0,0 +1,153 @@
+//go:build optimizeTest
+// +build optimizeTest
+
+package optimize
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"granny-pass/internal/provider/layout"
+)
+
+func TestAnnealer(t *testing.T) {
+	t.Run("test annealer functions", func(t *testing.T) {
+		var (
+			keys = []string{"a", "b", "c", "d"}
+			a    *Annealer
+			res  Result
+			n    int
+			err  error
+		)
+
+		//keys on a line, the cost is the count of keys between them
+		line := make(map[string]map[string]int, len(keys))
+		for i, k := range keys {
+			line[k] = make(map[string]int, len(keys))
+			for j, k2 := range keys {
+				line[k][k2] = i - j
+				if j > i {
+					line[k][k2] = j - i
+				}
+			}
+		}
+
+		t.Run("NewAnnealer", func(t *testing.T) {
+			a, err = NewAnnealer(line, keys, []string{"adadad", "a d", "ax"})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, a.Skipped)
+			assert.Equal(t, DefaultIterations, a.Iterations)
+
+			_, err = NewAnnealer(line, keys[:1], []string{"a"})
+			assert.ErrorIs(t, err, ErrNoKeys)
+
+			_, err = NewAnnealer(line, keys, []string{"xyz"})
+			assert.ErrorIs(t, err, ErrNoTexts)
+
+			_, err = NewAnnealer(line, []string{"a", "e"}, []string{"a"})
+			assert.ErrorIs(t, err, ErrUnknownKey)
+		})
+
+		t.Run("Cost", func(t *testing.T) {
+			n, err = a.Cost(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, 18, n)
+
+			n, err = a.Cost(map[string]string{"b": "d", "d": "b"})
+			assert.NoError(t, err)
+			assert.Equal(t, 6, n)
+
+			_, err = a.Cost(map[string]string{"b": "d"})
+			assert.ErrorIs(t, err, ErrUnknownKey)
+		})
+
+		t.Run("Run", func(t *testing.T) {
+			a.Iterations = 1000
+			res, err = a.Run()
+			assert.NoError(t, err)
+			assert.Equal(t, 18, res.Initial)
+			assert.Equal(t, 6, res.Cost)
+			assert.Equal(t, 2, res.Texts)
+			assert.InDelta(t, 3.0, res.Average(), 1e-9)
+
+			n, err = a.Cost(res.Placement)
+			assert.NoError(t, err)
+			assert.Equal(t, res.Cost, n)
+
+			a.Iterations = -1
+			_, err = a.Run()
+			assert.ErrorIs(t, err, ErrWrongOptions)
+		})
+
+		t.Run("partial keys", func(t *testing.T) {
+			//only c and d are moved, bigrams with fixed a are counted
+			a, err = NewAnnealer(line, []string{"c", "d"}, []string{"adadad", "ab"})
+			assert.NoError(t, err)
+			assert.Equal(t, 0, a.Skipped)
+			a.Iterations = 100
+
+			res, err = a.Run()
+			assert.NoError(t, err)
+			assert.Equal(t, 16, res.Initial)
+			assert.Equal(t, 11, res.Cost)
+			assert.Equal(t, map[string]string{"c": "d", "d": "c"}, res.Placement)
+
+			_, err = NewAnnealer(line, []string{"c", "c"}, []string{"ab"})
+			assert.ErrorIs(t, err, ErrNoKeys)
+		})
+
+		t.Run("delta", func(t *testing.T) {
+			//asymmetric costs
+			rnd := rand.New(rand.NewSource(2))
+			dist := make(map[string]map[string]int, len(keys))
+			for _, k := range keys {
+				dist[k] = make(map[string]int, len(keys))
+				for _, k2 := range keys {
+					dist[k][k2] = rnd.Intn(10)
+				}
+			}
+
+			a, err = NewAnnealer(dist, keys, []string{"abcd", "dcba", "aab", "cdc", "bd"})
+			assert.NoError(t, err)
+
+			pos := []int{0, 1, 2, 3}
+			for s := 0; s < 50; s++ {
+				i, j := rnd.Intn(4), rnd.Intn(4)
+				if i == j {
+					continue
+				}
+				before := a.cost(pos)
+				d := a.delta(pos, i, j)
+				pos[i], pos[j] = pos[j], pos[i]
+				assert.Equal(t, a.cost(pos)-before, d)
+			}
+		})
+
+		t.Run("grid layout", func(t *testing.T) {
+			l, err := layout.NewGrid("abc", []string{"abc", "def", "ghi"}, false, 0)
+			assert.NoError(t, err)
+
+			dist, err := l.CostMap(layout.CostModel{Objective: layout.ObjectiveHops, Mode: layout.ModeTask, MaxPathLen: 20})
+			assert.NoError(t, err)
+
+			a, err = NewAnnealer(dist, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}, []string{"aih", "hia", "ci", "bad"})
+			assert.NoError(t, err)
+			a.Iterations = 20000
+
+			res, err = a.Run()
+			assert.NoError(t, err)
+			assert.Less(t, res.Cost, res.Initial)
+			//every bigram is typed with one move
+			assert.Equal(t, 7, res.Cost)
+
+			r, err := l.Rename(res.Placement)
+			assert.NoError(t, err)
+			m, err := r.CostMap(layout.CostModel{Objective: layout.ObjectiveHops, Mode: layout.ModeTask, MaxPathLen: 20})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, m["a"]["i"])
+			assert.Equal(t, 1, m["i"]["h"])
+		})
+	})
+}
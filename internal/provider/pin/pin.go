@@ -0,0 +1,274 @@
+package pin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultPenalty is added to the cost of the code for every trivial pattern, in moves to the nearest key
+	DefaultPenalty = 10
+	// MaxLength limits length of the code, the search is exponential
+	MaxLength = 12
+
+	// minDistinct - codes with fewer distinct digits are trivial, e.g. 1121
+	minDistinct = 3
+)
+
+// Pattern is a kind of trivial code which is easy to guess
+type Pattern string
+
+const (
+	// PatternRepeat - the same digit twice in a row, e.g. 1135
+	PatternRepeat Pattern = "repeat"
+	// PatternBounce - return to the digit before the last one, e.g. 1214
+	PatternBounce Pattern = "bounce"
+	// PatternLine - three keys in a row on a straight line with equal steps, e.g. 147 or 159 on phone keypad
+	PatternLine Pattern = "line"
+	// PatternSequence - three digits in ascending or descending order, e.g. 345 or 987
+	PatternSequence Pattern = "sequence"
+	// PatternFewDigits - fewer than three distinct digits in the code or than a half of the long code, e.g. 1121
+	PatternFewDigits Pattern = "few digits"
+)
+
+var (
+	ErrNoDigits     = errors.New("keypad has no digits")
+	ErrWrongLength  = errors.New("wrong code length")
+	ErrWrongCount   = errors.New("wrong count of codes")
+	ErrUnknownDigit = errors.New("unknown digit")
+)
+
+// Point is the center of the key
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Code is a generated numeric code with its cost
+type Code struct {
+	PIN string
+	// Travel - sum of distances between consecutive keys
+	Travel int
+	// Patterns - trivial patterns found in the code, every occurrence is listed
+	Patterns []Pattern
+	// Cost - travel plus penalty for every pattern, codes are sorted by it
+	Cost int
+}
+
+// Generator searches numeric codes with low travel between keys of the keypad, trivial patterns are penalized
+type Generator struct {
+	// Penalty is added to the cost for every trivial pattern, in moves to the nearest key
+	Penalty int
+	// unit - the shortest distance between different digit keys
+	unit   int
+	digits []string
+	dist   map[string]map[string]int
+	pos    map[string]Point
+}
+
+// NewGenerator returns generator for digit keys of the distance map, e.g. made by layout.CostMap,
+// the penalty is multiplied by the shortest distance between keys, so it suits any objective.
+// Centers of keys are used to find straight lines, lines are not searched if pos is nil
+func NewGenerator(dist map[string]map[string]int, pos map[string]Point, penalty int) (*Generator, error) {
+	g := &Generator{
+		Penalty: penalty,
+		dist:    dist,
+		pos:     pos,
+	}
+
+	for d := '0'; d <= '9'; d++ {
+		if _, ok := dist[string(d)]; ok {
+			g.digits = append(g.digits, string(d))
+		}
+	}
+
+	if len(g.digits) < minDistinct {
+		return nil, fmt.Errorf("%w: %d digit keys", ErrNoDigits, len(g.digits))
+	}
+
+	g.unit = math.MaxInt
+	for _, d1 := range g.digits {
+		for _, d2 := range g.digits {
+			if n := dist[d1][d2]; d1 != d2 && n > 0 && n < g.unit {
+				g.unit = n
+			}
+		}
+	}
+	if g.unit == math.MaxInt {
+		g.unit = 1
+	}
+	return g, nil
+}
+
+// Travel returns sum of distances between consecutive keys of the code
+func (g *Generator) Travel(pin string) (int, error) {
+	sum := 0
+	for i := 0; i < len(pin); i++ {
+		if _, ok := g.dist[pin[i:i+1]]; !ok {
+			return 0, fmt.Errorf("%w: %q", ErrUnknownDigit, pin[i])
+		}
+		if i > 0 {
+			sum += g.dist[pin[i-1:i]][pin[i:i+1]]
+		}
+	}
+	return sum, nil
+}
+
+// Patterns returns trivial patterns found in the code
+func (g *Generator) Patterns(pin string) []Pattern {
+	var res []Pattern
+
+	for i := 1; i < len(pin); i++ {
+		res = g.patternsAt(res, pin, i)
+	}
+
+	if n := distinct(pin); len(pin) >= minDistinct && (n < minDistinct || 2*n < len(pin)) {
+		res = append(res, PatternFewDigits)
+	}
+	return res
+}
+
+// patternsAt appends patterns ending at the digit i of the code
+func (g *Generator) patternsAt(res []Pattern, pin string, i int) []Pattern {
+	if pin[i] == pin[i-1] {
+		res = append(res, PatternRepeat)
+	}
+	if i < 2 {
+		return res
+	}
+	if pin[i] == pin[i-2] && pin[i] != pin[i-1] {
+		res = append(res, PatternBounce)
+	}
+	if g.isLine(pin[i-2:i-1], pin[i-1:i], pin[i:i+1]) {
+		res = append(res, PatternLine)
+	}
+	if step := int(pin[i]) - int(pin[i-1]); (step == 1 || step == -1) && int(pin[i-1])-int(pin[i-2]) == step {
+		res = append(res, PatternSequence)
+	}
+	return res
+}
+
+// Check returns the code with its travel, patterns and cost
+func (g *Generator) Check(pin string) (Code, error) {
+	travel, err := g.Travel(pin)
+	if err != nil {
+		return Code{}, err
+	}
+
+	patterns := g.Patterns(pin)
+	return Code{
+		PIN:      pin,
+		Travel:   travel,
+		Patterns: patterns,
+		Cost:     travel + g.penalty(len(patterns)),
+	}, nil
+}
+
+// Best returns count codes of the length with the lowest cost, codes with equal cost are sorted by PIN.
+// Branch and bound: the code is not extended when its travel and penalties for patterns found so far
+// already exceed the cost of the worst kept code
+func (g *Generator) Best(length, count int) ([]Code, error) {
+	if length < 1 || length > MaxLength {
+		return nil, fmt.Errorf("%w: %d, expected 1..%d", ErrWrongLength, length, MaxLength)
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrWrongCount, count)
+	}
+
+	var (
+		res      []Code
+		bound    = math.MaxInt
+		code     = make([]byte, 0, length)
+		patterns = make([]Pattern, 0, 2*length)
+	)
+
+	var search func(cost int)
+	search = func(cost int) {
+		if cost > bound {
+			return
+		}
+
+		if len(code) == length {
+			c, _ := g.Check(string(code))
+			if c.Cost > bound {
+				return
+			}
+			res = insert(res, c, count)
+			if len(res) == count {
+				bound = res[len(res)-1].Cost
+			}
+			return
+		}
+
+		for _, d := range g.digits {
+			code = append(code, d[0])
+
+			next := cost
+			if i := len(code) - 1; i > 0 {
+				next += g.dist[string(code[i-1])][d]
+				next += g.penalty(len(g.patternsAt(patterns[:0], string(code), i)))
+			}
+			search(next)
+
+			code = code[:len(code)-1]
+		}
+	}
+	search(0)
+
+	return res, nil
+}
+
+// penalty returns the cost of n trivial patterns
+func (g *Generator) penalty(n int) int {
+	return g.Penalty * g.unit * n
+}
+
+// isLine reports whether three different keys lie on a straight line with equal steps
+func (g *Generator) isLine(a, b, c string) bool {
+	if g.pos == nil || a == b || b == c {
+		return false
+	}
+
+	pa, ok1 := g.pos[a]
+	pb, ok2 := g.pos[b]
+	pc, ok3 := g.pos[c]
+	if !ok1 || !ok2 || !ok3 {
+		return false
+	}
+
+	const eps = 1e-9
+	return math.Abs((pb.X-pa.X)-(pc.X-pb.X)) < eps && math.Abs((pb.Y-pa.Y)-(pc.Y-pb.Y)) < eps
+}
+
+// insert adds the code keeping codes sorted by cost and PIN, only count best codes are kept
+func insert(codes []Code, c Code, count int) []Code {
+	i := sort.Search(len(codes), func(i int) bool {
+		return codes[i].Cost > c.Cost || (codes[i].Cost == c.Cost && codes[i].PIN > c.PIN)
+	})
+	if i >= count {
+		return codes
+	}
+
+	codes = append(codes, Code{})
+	copy(codes[i+1:], codes[i:])
+	codes[i] = c
+
+	if len(codes) > count {
+		codes = codes[:count]
+	}
+	return codes
+}
+
+func distinct(pin string) int {
+	var seen [256]bool
+	n := 0
+	for i := 0; i < len(pin); i++ {
+		if !seen[pin[i]] {
+			seen[pin[i]] = true
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,123 @@
+//go:build pinTest
+// +build pinTest
+
+package pin
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"granny-pass/internal/provider/layout"
+)
+
+func TestGenerator(t *testing.T) {
+	t.Run("test pin generator functions", func(t *testing.T) {
+		var (
+			g     *Generator
+			codes []Code
+			c     Code
+			n     int
+			err   error
+		)
+
+		l, err := layout.Load("../../../layouts/phone.json")
+		assert.NoError(t, err)
+
+		dist, err := l.CostMap(layout.CostModel{Objective: layout.ObjectiveHops, Mode: layout.ModeTask, MaxPathLen: 20})
+		assert.NoError(t, err)
+
+		pos := make(map[string]Point, len(l.Keys))
+		for _, k := range l.Keys {
+			x, y := k.Center()
+			pos[k.Name] = Point{X: x, Y: y}
+		}
+
+		t.Run("NewGenerator", func(t *testing.T) {
+			g, err = NewGenerator(dist, pos, DefaultPenalty)
+			assert.NoError(t, err)
+			assert.Equal(t, 10, len(g.digits))
+
+			_, err = NewGenerator(map[string]map[string]int{"a": {"a": 0}}, nil, DefaultPenalty)
+			assert.ErrorIs(t, err, ErrNoDigits)
+		})
+
+		t.Run("Travel", func(t *testing.T) {
+			n, err = g.Travel("1590")
+			assert.NoError(t, err)
+			assert.Equal(t, 2+2+2, n)
+
+			_, err = g.Travel("12a")
+			assert.ErrorIs(t, err, ErrUnknownDigit)
+		})
+
+		t.Run("Patterns", func(t *testing.T) {
+			assert.Equal(t, []Pattern{PatternRepeat, PatternRepeat, PatternRepeat, PatternFewDigits}, g.Patterns("1111"))
+			assert.Equal(t, []Pattern{PatternLine, PatternSequence}, g.Patterns("1230"))
+			assert.Equal(t, []Pattern{PatternLine}, g.Patterns("1478"))
+			assert.Equal(t, []Pattern{PatternLine}, g.Patterns("7530"))
+			assert.Equal(t, []Pattern{PatternBounce, PatternBounce, PatternFewDigits}, g.Patterns("1212"))
+			assert.Equal(t, []Pattern{PatternFewDigits}, g.Patterns("12412412"))
+			assert.Equal(t, 0, len(g.Patterns("1524")))
+
+			//lines are not searched without positions
+			ng, _ := NewGenerator(dist, nil, DefaultPenalty)
+			assert.Equal(t, 0, len(ng.Patterns("1478")))
+		})
+
+		t.Run("penalty in moves to the nearest key", func(t *testing.T) {
+			mm, err := l.CostMap(layout.CostModel{Objective: layout.ObjectiveDistance})
+			assert.NoError(t, err)
+
+			dg, err := NewGenerator(mm, pos, DefaultPenalty)
+			assert.NoError(t, err)
+			assert.Equal(t, 19, dg.unit)
+
+			c, err = dg.Check("1111")
+			assert.NoError(t, err)
+			assert.Equal(t, 4*DefaultPenalty*19, c.Cost)
+		})
+
+		t.Run("Check", func(t *testing.T) {
+			c, err = g.Check("1230")
+			assert.NoError(t, err)
+			assert.Equal(t, 1+1+4, c.Travel)
+			assert.Equal(t, c.Travel+2*DefaultPenalty, c.Cost)
+		})
+
+		t.Run("Best", func(t *testing.T) {
+			codes, err = g.Best(4, 5)
+			assert.NoError(t, err)
+			assert.Equal(t, 5, len(codes))
+
+			//brute force
+			var all []Code
+			for i := 0; i < 10000; i++ {
+				c, _ = g.Check(fmt.Sprintf("%04d", i))
+				all = append(all, c)
+			}
+			sort.Slice(all, func(i, j int) bool {
+				return all[i].Cost < all[j].Cost || (all[i].Cost == all[j].Cost && all[i].PIN < all[j].PIN)
+			})
+			assert.Equal(t, all[:5], codes)
+
+			for _, c := range codes {
+				assert.Equal(t, 0, len(c.Patterns), c.PIN)
+			}
+
+			_, err = g.Best(0, 5)
+			assert.ErrorIs(t, err, ErrWrongLength)
+			_, err = g.Best(4, 0)
+			assert.ErrorIs(t, err, ErrWrongCount)
+		})
+
+		t.Run("Best long code", func(t *testing.T) {
+			codes, err = g.Best(8, 3)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, len(codes))
+			assert.Equal(t, 8, len(codes[0].PIN))
+		})
+	})
+}
@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// beamNode is a partial password: the last word and the link to the previous node
+type beamNode struct {
+	parent  *beamNode
+	item    *wordMetric
+	state   State
+	length  int
+	pathLen int
+}
+
+type beamKey struct {
+	state  State
+	length int
+}
+
+// BeamSearch chooses wordCnt words with total length from minLen to maxLen and the smallest cost of the model.
+// Unlike KnapsackTable it works with any TypingModel, the cost may depend on all previous keystrokes.
+// On every step only width best partial passwords of every length are kept, partial passwords with the same state
+// and length are interchangeable, so only the cheapest of them is kept
+func (v *vocab) BeamSearch(m TypingModel, items []*wordMetric, width int) (knapsack, int, error) {
+	if width < 1 {
+		return knapsack{}, 0, fmt.Errorf("wrong beam width: %d", width)
+	}
+
+	beam := []*beamNode{{state: m.Init()}}
+
+	for cnt := uint8(1); cnt <= v.wordCnt; cnt++ {
+		best := make(map[beamKey]*beamNode)
+
+		for _, node := range beam {
+			for _, item := range items {
+				length := node.length + len(item.word)
+				if length > v.maxLen || node.contains(item) {
+					continue
+				}
+
+				n, state, err := m.Word(node.state, item.word)
+				if err != nil {
+					return knapsack{}, 0, err
+				}
+
+				key := beamKey{state: state, length: length}
+				if b, ok := best[key]; ok && b.pathLen <= node.pathLen+n {
+					continue
+				}
+				best[key] = &beamNode{
+					parent:  node,
+					item:    item,
+					state:   state,
+					length:  length,
+					pathLen: node.pathLen + n,
+				}
+			}
+		}
+
+		beam = v.cutBeam(best, width)
+	}
+
+	var res *beamNode
+	minPathLen := math.MaxInt
+	for _, node := range beam {
+		if node.length < v.minLen {
+			continue
+		}
+		if n := node.pathLen + m.Final(node.state); n < minPathLen {
+			minPathLen = n
+			res = node
+		}
+	}
+
+	if res == nil {
+		return knapsack{}, minPathLen, nil
+	}
+	return res.knapsack(minPathLen), minPathLen, nil
+}
+
+// cutBeam keeps width cheapest nodes of every length, so short cheap words do not push out the long ones
+func (v *vocab) cutBeam(best map[beamKey]*beamNode, width int) []*beamNode {
+	byLength := make([][]*beamNode, v.maxLen+1)
+	for _, node := range best {
+		byLength[node.length] = append(byLength[node.length], node)
+	}
+
+	var beam []*beamNode
+	for _, nodes := range byLength {
+		sort.Slice(nodes, func(i, j int) bool {
+			if nodes[i].pathLen != nodes[j].pathLen {
+				return nodes[i].pathLen < nodes[j].pathLen
+			}
+			return nodes[i].state < nodes[j].state
+		})
+		if len(nodes) > width {
+			nodes = nodes[:width]
+		}
+		beam = append(beam, nodes...)
+	}
+	return beam
+}
+
+func (b *beamNode) contains(item *wordMetric) bool {
+	for n := b; n != nil; n = n.parent {
+		if n.item == item {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *beamNode) knapsack(pathLen int) knapsack {
+	var items []*wordMetric
+	for n := b; n.item != nil; n = n.parent {
+		items = append([]*wordMetric{n.item}, items...)
+	}
+
+	return knapsack{
+		items:   items,
+		pathLen: pathLen,
+	}
+}
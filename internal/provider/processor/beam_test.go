@@ -0,0 +1,90 @@
+//go:build processorTest
+// +build processorTest
+
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeamSearch(t *testing.T) {
+	t.Run("test beam search", func(t *testing.T) {
+		var (
+			err         error
+			k           knapsack
+			pathLen, n  int
+			wordMetrics []*wordMetric
+			m           TypingModel
+		)
+
+		v := NewVocab(getDistanceMapForTests(), 4, 6, 2)
+		wordMetrics, err = v.ReadFile("testdata/test3.txt", true)
+		assert.NoError(t, err)
+
+		t.Run("wrong width", func(t *testing.T) {
+			_, _, err = v.BeamSearch(v.BigramModel(), wordMetrics, 0)
+			assert.Error(t, err)
+		})
+
+		t.Run("BigramModel gives the same as KnapsackTable", func(t *testing.T) {
+			k, pathLen, err = v.BeamSearch(v.BigramModel(), wordMetrics, 100)
+			assert.NoError(t, err)
+			assert.Equal(t, 4, pathLen)
+			assert.Equal(t, "ploki", k.GetDescription())
+			assert.Equal(t, pathLen, k.pathLen)
+		})
+
+		t.Run("FingerModel", func(t *testing.T) {
+			m, err = v.FingerModel(twoIndexFingers)
+			assert.NoError(t, err)
+
+			k, pathLen, err = v.BeamSearch(m, wordMetrics, 100)
+			assert.NoError(t, err)
+			fmt.Printf("pathLen: %d\npassword: %s\nwords: %s\n", pathLen, k.GetDescription(), k.GetDescriptionWithSpace())
+
+			assert.Equal(t, 2, len(k.items))
+			assert.Equal(t, true, k.Length() >= 4 && k.Length() <= 6)
+
+			var words []string
+			for _, item := range k.items {
+				words = append(words, item.word)
+			}
+			n, err = TypingCost(m, words)
+			assert.NoError(t, err)
+			assert.Equal(t, n, pathLen)
+
+			// two fingers are never worse than one
+			assert.Equal(t, true, pathLen <= 4)
+		})
+
+		t.Run("out5a.txt", func(t *testing.T) {
+			v = NewVocab(getDistanceMapForTests(), 20, 24, 4)
+			wordMetrics, err = v.ReadFile("testdata/out5a.txt", true)
+			assert.NoError(t, err)
+
+			k, pathLen, err = v.BeamSearch(v.BigramModel(), wordMetrics, 1000)
+			assert.NoError(t, err)
+			fmt.Printf("pathLen: %d\npassword: %s\nwords: %s\n", pathLen, k.GetDescription(), k.GetDescriptionWithSpace())
+
+			n, err = v.PathLen(k.GetDescription())
+			assert.NoError(t, err)
+			assert.Equal(t, n, pathLen)
+			// KnapsackTable gives 16 too
+			assert.Equal(t, 16, pathLen)
+			assert.Equal(t, 4, len(k.items))
+		})
+
+		t.Run("nothing fits", func(t *testing.T) {
+			v = NewVocab(getDistanceMapForTests(), 40, 50, 2)
+			wordMetrics, err = v.ReadFile("testdata/test3.txt", true)
+			assert.NoError(t, err)
+
+			k, _, err = v.BeamSearch(v.BigramModel(), wordMetrics, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, true, k.isEmpty())
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultSharpAngle - turns of the swipe sharper than 90 degrees slow down the finger
+const DefaultSharpAngle = math.Pi / 2
+
+var ErrNoKeyCenter = errors.New("no key center for the letter")
+
+// Point is the center of the key, e.g. in millimetres
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Gesture is the cost of swipe typing on the touchscreen: the finger glides through centers of the word letters,
+// the cost is the length of the polyline plus the penalty for sharp turns. Between words the finger is lifted,
+// so the gap is the straight move of distanceArray
+type Gesture struct {
+	// Centers - centers of letter keys
+	Centers map[uint8]Point
+	// TurnPenalty - cost of the U-turn in units of Centers, a turn sharper than SharpAngle costs its part by the angle
+	TurnPenalty float64
+	// SharpAngle - turns by a smaller angle in radians are free
+	SharpAngle float64
+}
+
+// SetGesture makes PathLen the cost of swiping the word, distanceArray should contain straight distances
+// between key centers in the same units for gaps between words. nil returns to typing key by key
+func (v *vocab) SetGesture(g *Gesture) {
+	v.gesture = g
+}
+
+// Cost returns the length of the swipe through the word letters with penalties for sharp turns, rounded.
+// Repeated letters do not move the finger
+func (g *Gesture) Cost(word string) (int, error) {
+	var (
+		sum, dx, dy float64
+		prev        Point
+		moved       bool
+	)
+
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return 0, fmt.Errorf("wrong symbol: %v", word[i])
+		}
+
+		p, ok := g.Centers[word[i]]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrNoKeyCenter, word[i])
+		}
+
+		if i > 0 {
+			x, y := p.X-prev.X, p.Y-prev.Y
+			d := math.Hypot(x, y)
+			if d == 0 {
+				continue
+			}
+			sum += d
+
+			if moved {
+				cos := (x*dx + y*dy) / (d * math.Hypot(dx, dy))
+				if angle := math.Acos(math.Max(-1, math.Min(1, cos))); angle > g.SharpAngle {
+					sum += g.TurnPenalty * angle / math.Pi
+				}
+			}
+			dx, dy, moved = x, y, true
+		}
+		prev = p
+	}
+
+	return int(math.Round(sum)), nil
+}
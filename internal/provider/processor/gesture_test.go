@@ -0,0 +1,88 @@
+//go:build processorTest
+// +build processorTest
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGesture(t *testing.T) {
+	t.Run("test gesture functions", func(t *testing.T) {
+		var (
+			n   int
+			err error
+		)
+
+		g := &Gesture{
+			Centers: map[uint8]Point{
+				'a': {X: 0, Y: 0},
+				'b': {X: 1, Y: 0},
+				'c': {X: 2, Y: 0},
+				'd': {X: 1, Y: 1},
+			},
+			TurnPenalty: 4,
+			SharpAngle:  DefaultSharpAngle,
+		}
+
+		t.Run("Cost", func(t *testing.T) {
+			n, err = g.Cost("abc")
+			assert.NoError(t, err)
+			assert.Equal(t, 2, n)
+
+			//U-turn costs the full penalty
+			n, err = g.Cost("aba")
+			assert.NoError(t, err)
+			assert.Equal(t, 2+4, n)
+
+			//right angle is not sharp
+			n, err = g.Cost("abd")
+			assert.NoError(t, err)
+			assert.Equal(t, 2, n)
+
+			//2 + sqrt(2) + 4*135/180
+			n, err = g.Cost("acd")
+			assert.NoError(t, err)
+			assert.Equal(t, 6, n)
+
+			n, err = g.Cost("aabbc")
+			assert.NoError(t, err)
+			assert.Equal(t, 2, n)
+
+			_, err = g.Cost("abz")
+			assert.ErrorIs(t, err, ErrNoKeyCenter)
+
+			_, err = g.Cost("ab1")
+			assert.Error(t, err)
+		})
+
+		t.Run("PathLen with gesture", func(t *testing.T) {
+			v := NewVocab(getDistanceMapForTests(), 0, 0, 0)
+			gap, err := v.GapPathLen("aba", "c")
+			assert.NoError(t, err)
+			typed, err := v.PathLen("aba")
+			assert.NoError(t, err)
+
+			v.SetGesture(g)
+			n, err = v.PathLen("aba")
+			assert.NoError(t, err)
+			assert.Equal(t, 6, n)
+
+			//the finger is lifted between words
+			n, err = v.GapPathLen("aba", "c")
+			assert.NoError(t, err)
+			assert.Equal(t, gap, n)
+
+			n, err = TypingCost(v.BigramModel(), []string{"aba", "c"})
+			assert.NoError(t, err)
+			assert.Equal(t, 6+gap, n)
+
+			v.SetGesture(nil)
+			n, err = v.PathLen("aba")
+			assert.NoError(t, err)
+			assert.Equal(t, typed, n)
+		})
+	})
+}
@@ -11,7 +11,7 @@ type wordMetric struct {
 
 type knapsack struct {
 	items   []*wordMetric
-	pathLen int // в классической интерпретации price - ценность предметов
+	pathLen int // в классической интерпретации price - ценность предметов; включает путь от стартовой и до конечной клавиши
 }
 
 type Features interface {
@@ -81,10 +81,7 @@ func (v *vocab) calcSet(i, j int, wm *wordMetric, kt *[][][]knapsack) error {
 		candidateKnapsacks = make([]knapsack, v.wordCnt+1)
 
 		//слово подходит впритык или с запасом, фиксируем его как кандидата для наполнения рюкзака из 1 слова
-		candidateKnapsacks[1] = knapsack{
-			items:   []*wordMetric{wm},
-			pathLen: wm.pathLen,
-		}
+		candidateKnapsacks[1] = v.singleWordKnapsack(wm)
 
 		lenLeftover := j - len(wm.word)
 		if lenLeftover > 0 {
@@ -154,6 +151,8 @@ func (v *vocab) calcSet(i, j int, wm *wordMetric, kt *[][][]knapsack) error {
 // FindBestCombination insert word wm in different positions in knapsack k
 // return bool flag nedStop when gap==0
 // return new knapsack with the shortest pathLen
+// pathLen of knapsack includes moves from the start key and to the end key, so they are replaced
+// when the word becomes the first or the last one
 func (v *vocab) FindBestCombination(k knapsack, wm *wordMetric) (bool, knapsack, error) {
 	var (
 		g1, g2 int
@@ -164,24 +163,30 @@ func (v *vocab) FindBestCombination(k knapsack, wm *wordMetric) (bool, knapsack,
 		return false, k, fmt.Errorf("wm is not set, probably you got an empty line(word) in file")
 	}
 
+	if k.isEmpty() {
+		return true, v.singleWordKnapsack(wm), nil
+	}
+
 	//add in the front
 	g1, err = v.GapPathLen(wm.word, k.firstWord())
 	if err != nil {
 		return false, k, err
 	}
+	d1 := v.StartPathLen(wm.word) + g1 - v.StartPathLen(k.firstWord())
 
 	//add in the end
 	g2, err = v.GapPathLen(k.lastWord(), wm.word)
 	if err != nil {
 		return false, k, err
 	}
+	d2 := g2 + v.EndPathLen(wm.word) - v.EndPathLen(k.lastWord())
 
-	if g1 < g2 {
+	if d1 < d2 {
 		//add in the front
 		newItems := append([]*wordMetric{}, wm)
 		return g1 <= 1, knapsack{
 			items:   append(newItems, k.items...),
-			pathLen: wm.pathLen + g1 + k.pathLen,
+			pathLen: wm.pathLen + d1 + k.pathLen,
 		}, nil
 
 	}
@@ -190,11 +195,18 @@ func (v *vocab) FindBestCombination(k knapsack, wm *wordMetric) (bool, knapsack,
 	newItems := append([]*wordMetric{}, k.items...)
 	return g2 <= 1, knapsack{
 		items:   append(newItems, wm),
-		pathLen: k.pathLen + g2 + wm.pathLen,
+		pathLen: k.pathLen + d2 + wm.pathLen,
 	}, nil
 
 }
 
+func (v *vocab) singleWordKnapsack(wm *wordMetric) knapsack {
+	return knapsack{
+		items:   []*wordMetric{wm},
+		pathLen: v.StartPathLen(wm.word) + wm.pathLen + v.EndPathLen(wm.word),
+	}
+}
+
 // ChooseCandidate compare length, if equal than compare pathLen
 func (v *vocab) ChooseCandidate(candidateKs, upKs, leftKs []knapsack) []knapsack {
 	bestKs := candidateKs
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"granny-pass/internal/provider/graph"
 )
 
 type testParam struct {
@@ -229,6 +231,65 @@ func TestNewKnapsack(t *testing.T) {
 				})
 			}
 		})
+
+		t.Run("KnapsackTable and MinChoice with start and end keys", func(t *testing.T) {
+			m := getKeyboardMapForTests()
+			startDist, err := graph.FromKeyDistanceArray(m, "a")
+			assert.NoError(t, err)
+			endDist, err := graph.ToKeyDistanceArray(m, "a")
+			assert.NoError(t, err)
+
+			v = NewVocab(getDistanceMapForTests(), 4, 6, 2)
+			v.SetStartKey(startDist)
+			v.SetEndKey(endDist)
+
+			wordMetrics, err := v.ReadFile("testdata/test3.txt", true)
+			assert.NoError(t, err)
+
+			kt := v.KnapsackTable(wordMetrics)
+			for _, x := range *kt {
+				for _, y := range x {
+					for _, k1 := range y {
+						p, err := v.FullPathLen(k1.GetDescription())
+						assert.NoError(t, err)
+						assert.Equal(t, p, k1.pathLen)
+					}
+				}
+			}
+
+			k, minPathLen := v.MinChoice(kt)
+			fmt.Printf("pathLen: %d\npassword: %s\nwords: %s\n", minPathLen, k.GetDescription(), k.GetDescriptionWithSpace())
+
+			// without keys the best is "ploki", but p is too far from a
+			assert.Equal(t, 12, minPathLen)
+			assert.Equal(t, "athe", k.GetDescription())
+		})
+
+		t.Run("KnapsackTable and MinChoice with press cost", func(t *testing.T) {
+			v = NewVocab(getDistanceMapForTests(), 4, 6, 2)
+			v.SetPressCost(graph.KeyWeightArray(map[string]int{"p": 5, "q": 5}))
+
+			wordMetrics, err := v.ReadFile("testdata/test3.txt", true)
+			assert.NoError(t, err)
+
+			kt := v.KnapsackTable(wordMetrics)
+			for _, x := range *kt {
+				for _, y := range x {
+					for _, k1 := range y {
+						p, err := v.PathLen(k1.GetDescription())
+						assert.NoError(t, err)
+						assert.Equal(t, p, k1.pathLen)
+					}
+				}
+			}
+
+			k, minPathLen := v.MinChoice(kt)
+			fmt.Printf("pathLen: %d\npassword: %s\nwords: %s\n", minPathLen, k.GetDescription(), k.GetDescriptionWithSpace())
+
+			// "ploki" costs 4+5 now
+			assert.Equal(t, 7, minPathLen)
+			assert.Equal(t, "lokiv", k.GetDescription())
+		})
 	})
 }
 
@@ -1,16 +1,125 @@
 package processor
 
-import "errors"
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"sort"
+)
 
 var (
 	ErrOpenFile = errors.New("can not open file")
 	ErrScanFile = errors.New("can not scan file")
 )
 
+// ExclusionReport describes words dropped while reading the vocabulary because they contain excluded keys
+type ExclusionReport struct {
+	// Words - count of words in the file
+	Words int
+	// Dropped - count of dropped words
+	Dropped int
+	// ByKey - count of words containing every excluded letter, a word may be counted for several letters
+	ByKey map[string]int
+}
+
+// Lost returns the dropped part of the vocabulary from 0 to 1
+func (r ExclusionReport) Lost() float64 {
+	if r.Words == 0 {
+		return 0
+	}
+	return float64(r.Dropped) / float64(r.Words)
+}
+
+// PathReport describes costs of typing words of the vocabulary on the keyboard
+type PathReport struct {
+	// Words - count of words
+	Words int
+	// MeanPathLen - mean PathLen of the word
+	MeanPathLen float64
+	// Bigrams - count of bigrams inside words by their cost, every occurrence is counted
+	Bigrams map[int]int
+}
+
+// BigramCount returns count of bigrams inside words
+func (r PathReport) BigramCount() int {
+	n := 0
+	for _, cnt := range r.Bigrams {
+		n += cnt
+	}
+	return n
+}
+
+// BigramQuantile returns the cost not exceeded by the part q of bigrams, q from 0 to 1, e.g. 0.5 for the median
+func (r PathReport) BigramQuantile(q float64) int {
+	costs := make([]int, 0, len(r.Bigrams))
+	for cost := range r.Bigrams {
+		costs = append(costs, cost)
+	}
+	sort.Ints(costs)
+
+	rank := int(math.Ceil(q * float64(r.BigramCount())))
+	n := 0
+	for _, cost := range costs {
+		n += r.Bigrams[cost]
+		if n >= rank {
+			return cost
+		}
+	}
+	return 0
+}
+
+// WordCost is the word with its PathLen
+type WordCost struct {
+	Word    string `json:"word"`
+	PathLen int    `json:"path_len"`
+}
+
+// VocabularyStats describes words of the vocabulary file on the keyboard
+type VocabularyStats struct {
+	// Words - count of words in the file
+	Words int `json:"words"`
+	// Rejected - count of words rejected by PathLen, e.g. with capital letters, digits or apostrophes
+	Rejected int `json:"rejected"`
+	// Excluded - count of words with excluded keys
+	Excluded int `json:"excluded"`
+	// ByLength - count of accepted words by length
+	ByLength map[int]int `json:"by_length"`
+	// PathLens - count of accepted words by PathLen
+	PathLens map[int]int `json:"path_lens"`
+	// MeanPathLen - mean PathLen of accepted words
+	MeanPathLen float64 `json:"mean_path_len"`
+	// Cheapest - words with the lowest PathLen of every length, sorted by PathLen and the word
+	Cheapest map[int][]WordCost `json:"cheapest"`
+	// First, Last - count of accepted words by the first and by the last letter, they define costs of gaps
+	First map[string]int `json:"first_letters"`
+	Last  map[string]int `json:"last_letters"`
+}
+
+// RejectedPart returns the part of words rejected by PathLen from 0 to 1
+func (s VocabularyStats) RejectedPart() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.Rejected) / float64(s.Words)
+}
+
 type NewProcessor interface {
 	PathLen(word string) (int, error)
 	GapPathLen(word1, word2 string) (int, error)
+	FullPathLen(password string) (int, error)
+	StartPathLen(word string) int
+	EndPathLen(word string) int
+	SetStartKey(dist []int)
+	SetEndKey(dist []int)
+	SetPressCost(cost []int)
+	SetExcludedKeys(keys []string)
+	SetGesture(g *Gesture)
+	Exclusion() ExclusionReport
 	ReadFile(fileName string, needSort bool) ([]*wordMetric, error)
+	ReadFS(fsys fs.FS, fileName string, needSort bool) ([]*wordMetric, error)
+	Letters(items []*wordMetric) string
+	PathReport(items []*wordMetric) PathReport
+	Stats(fsys fs.FS, fileName string, cheapest int) (VocabularyStats, error)
 
 	calcSet(i, j int, wm *wordMetric, kt *[][][]knapsack) error
 	FindBestCombination(k knapsack, wm *wordMetric) (bool, knapsack, error)
@@ -18,6 +127,10 @@ type NewProcessor interface {
 
 	KnapsackTable(items []*wordMetric) *[][][]knapsack
 	MinChoice(kt *[][][]knapsack) (knapsack, int)
+
+	BigramModel() TypingModel
+	FingerModel(fingers []Finger) (TypingModel, error)
+	BeamSearch(m TypingModel, items []*wordMetric, width int) (knapsack, int, error)
 }
 
 func NewVocab(m []int, minLen, maxLen int, wordCnt uint8) NewProcessor {
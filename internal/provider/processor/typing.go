@@ -0,0 +1,185 @@
+package processor
+
+import (
+	"fmt"
+)
+
+// noPosition marks a finger which has not pressed anything yet and has no home key
+const noPosition = 0xFF
+
+// State is a typing state of a model, one byte per finger with the offset of the letter under it.
+// State is comparable, so states are used as keys while solving
+type State string
+
+// TypingModel is a state-dependent typing cost.
+// Unlike distanceArray the cost of a word depends not only on the previous letter
+type TypingModel interface {
+	// Init returns the state before the first keystroke
+	Init() State
+	// Word returns the cost of typing word in state s and the state after it
+	Word(s State, word string) (int, State, error)
+	// Final returns the cost of finishing typing in state s, e.g. move to the end key
+	Final(s State) int
+}
+
+// Finger describes one finger of the typing model
+type Finger struct {
+	Home uint8  // letter where the finger rests before typing, 0 if the first move is free
+	Keys string // letters pressed by this finger
+}
+
+// bigramModel is the one-finger model of vocab: the state is the last typed letter
+type bigramModel struct {
+	v *vocab
+}
+
+// fingerModel is the model of several fingers, every letter is pressed by its own finger
+// and the cost of a keystroke is the move of that finger from its current position
+type fingerModel struct {
+	v       *vocab
+	homes   State
+	fingers [32]uint8 // letter offset -> finger number
+}
+
+// BigramModel returns the one-finger model equal to PathLen, GapPathLen, StartPathLen and EndPathLen
+func (v *vocab) BigramModel() TypingModel {
+	return &bigramModel{v: v}
+}
+
+// FingerModel returns the model of several fingers with distances and press costs of vocab
+func (v *vocab) FingerModel(fingers []Finger) (TypingModel, error) {
+	if len(fingers) == 0 || len(fingers) >= noPosition {
+		return nil, fmt.Errorf("wrong count of fingers: %d", len(fingers))
+	}
+
+	m := &fingerModel{v: v}
+	for i := range m.fingers {
+		m.fingers[i] = noPosition
+	}
+
+	homes := make([]byte, len(fingers))
+	for n, f := range fingers {
+		homes[n] = noPosition
+		if f.Home != 0 {
+			if f.Home < 'a' || f.Home > 'z' {
+				return nil, fmt.Errorf("wrong home key of finger %d: %q", n+1, f.Home)
+			}
+			homes[n] = byte(symbolOffset(f.Home))
+		}
+
+		for i := 0; i < len(f.Keys); i++ {
+			if f.Keys[i] < 'a' || f.Keys[i] > 'z' {
+				return nil, fmt.Errorf("wrong key of finger %d: %q", n+1, f.Keys[i])
+			}
+			o := symbolOffset(f.Keys[i])
+			if m.fingers[o] != noPosition {
+				return nil, fmt.Errorf("key %q is assigned to fingers %d and %d", f.Keys[i], m.fingers[o]+1, n+1)
+			}
+			m.fingers[o] = uint8(n)
+		}
+	}
+	m.homes = State(homes)
+
+	return m, nil
+}
+
+func (m *bigramModel) Init() State {
+	return ""
+}
+
+func (m *bigramModel) Word(s State, word string) (int, State, error) {
+	l := len(word)
+	if l == 0 {
+		return 0, s, nil
+	}
+
+	n, err := m.v.PathLen(word)
+	if err != nil {
+		return 0, s, err
+	}
+
+	if s == "" {
+		n += m.v.StartPathLen(word)
+	} else {
+		n += m.v.distanceArray[getIndex(int(s[0]), symbolOffset(word[0]))]
+	}
+
+	return n, State([]byte{byte(symbolOffset(word[l-1]))}), nil
+}
+
+func (m *bigramModel) Final(s State) int {
+	if s == "" || m.v.endArray == nil {
+		return 0
+	}
+	return m.v.endArray[s[0]]
+}
+
+func (m *fingerModel) Init() State {
+	return m.homes
+}
+
+func (m *fingerModel) Word(s State, word string) (int, State, error) {
+	sum := 0
+	pos := []byte(s)
+
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return 0, s, fmt.Errorf("wrong symbol: %v", word[i])
+		}
+
+		o := symbolOffset(word[i])
+		f := m.fingers[o]
+		if f == noPosition {
+			return 0, s, fmt.Errorf("key %q is not assigned to any finger", word[i])
+		}
+
+		if pos[f] != noPosition {
+			sum += m.v.distanceArray[getIndex(int(pos[f]), o)]
+		}
+		if m.v.pressArray != nil {
+			sum += m.v.pressArray[o]
+		}
+		pos[f] = byte(o)
+	}
+
+	return sum, State(pos), nil
+}
+
+// Final returns the move to the end key of the nearest finger
+func (m *fingerModel) Final(s State) int {
+	if m.v.endArray == nil {
+		return 0
+	}
+
+	res := -1
+	for _, p := range []byte(s) {
+		if p == noPosition {
+			continue
+		}
+		if res < 0 || m.v.endArray[p] < res {
+			res = m.v.endArray[p]
+		}
+	}
+
+	if res < 0 {
+		return 0
+	}
+	return res
+}
+
+// TypingCost returns the cost of typing words one by one with the model
+func TypingCost(m TypingModel, words []string) (int, error) {
+	sum := 0
+	s := m.Init()
+
+	for _, word := range words {
+		n, next, err := m.Word(s, word)
+		if err != nil {
+			return 0, err
+		}
+		sum += n
+		s = next
+	}
+
+	return sum + m.Final(s), nil
+}
@@ -0,0 +1,133 @@
+//go:build processorTest
+// +build processorTest
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"granny-pass/internal/provider/graph"
+)
+
+var twoIndexFingers = []Finger{
+	{Home: 'f', Keys: "qwertasdfgzxcvb"},
+	{Home: 'j', Keys: "yuiophjklnm"},
+}
+
+func TestTypingModel(t *testing.T) {
+	t.Run("test typing models", func(t *testing.T) {
+		var (
+			n, p int
+			err  error
+			m    TypingModel
+		)
+
+		km := getKeyboardMapForTests()
+		v := NewVocab(getDistanceMapForTests(), 0, 0, 0)
+
+		t.Run("BigramModel", func(t *testing.T) {
+			m = v.BigramModel()
+
+			n, err = TypingCost(m, []string{"the", "cafe", "tanya"})
+			assert.NoError(t, err)
+			p, err = v.FullPathLen("thecafetanya")
+			assert.NoError(t, err)
+			assert.Equal(t, p, n)
+
+			t.Run("with start and end keys", func(t *testing.T) {
+				startDist, err := graph.FromKeyDistanceArray(km, "g")
+				assert.NoError(t, err)
+				endDist, err := graph.ToKeyDistanceArray(km, "l")
+				assert.NoError(t, err)
+
+				vk := NewVocab(getDistanceMapForTests(), 0, 0, 0)
+				vk.SetStartKey(startDist)
+				vk.SetEndKey(endDist)
+				vk.SetPressCost(graph.KeyWeightArray(map[string]int{"a": 1}))
+				m = vk.BigramModel()
+
+				n, err = TypingCost(m, []string{"the", "cafe", "tanya"})
+				assert.NoError(t, err)
+				p, err = vk.FullPathLen("thecafetanya")
+				assert.NoError(t, err)
+				assert.Equal(t, p, n)
+			})
+
+			_, err = TypingCost(m, []string{"the", "?"})
+			assert.Error(t, err)
+		})
+
+		t.Run("FingerModel", func(t *testing.T) {
+			m, err = v.FingerModel(twoIndexFingers)
+			assert.NoError(t, err)
+
+			n, err = TypingCost(m, []string{"fj"})
+			assert.NoError(t, err)
+			assert.Equal(t, 0, n)
+
+			// f -> t and j -> u
+			n, err = TypingCost(m, []string{"tu"})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, n)
+
+			// the right finger stays on u
+			n, err = TypingCost(m, []string{"tu", "gu"})
+			assert.NoError(t, err)
+			assert.Equal(t, 3, n)
+
+			p, err = v.PathLen("tugu")
+			assert.NoError(t, err)
+			assert.Equal(t, 6, p)
+
+			_, err = TypingCost(m, []string{"t1"})
+			assert.Error(t, err)
+
+			t.Run("without home keys", func(t *testing.T) {
+				m, err = v.FingerModel([]Finger{{Keys: "qwertasdfgzxcvb"}, {Keys: "yuiophjklnm"}})
+				assert.NoError(t, err)
+
+				n, err = TypingCost(m, []string{"tu"})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, n)
+			})
+
+			t.Run("with end key and press cost", func(t *testing.T) {
+				endDist, err := graph.ToKeyDistanceArray(km, "l")
+				assert.NoError(t, err)
+
+				vk := NewVocab(getDistanceMapForTests(), 0, 0, 0)
+				vk.SetEndKey(endDist)
+				vk.SetPressCost(graph.KeyWeightArray(map[string]int{"t": 1}))
+
+				m, err = vk.FingerModel(twoIndexFingers)
+				assert.NoError(t, err)
+
+				// f -> t, press t, j -> u, u -> l by the right finger
+				n, err = TypingCost(m, []string{"tu"})
+				assert.NoError(t, err)
+				assert.Equal(t, 1+1+1+3, n)
+			})
+
+			t.Run("wrong fingers", func(t *testing.T) {
+				_, err = v.FingerModel(nil)
+				assert.Error(t, err)
+
+				_, err = v.FingerModel([]Finger{{Keys: "qwe"}, {Keys: "ert"}})
+				assert.Error(t, err)
+
+				_, err = v.FingerModel([]Finger{{Home: '1', Keys: "qwe"}})
+				assert.Error(t, err)
+
+				_, err = v.FingerModel([]Finger{{Keys: "qW"}})
+				assert.Error(t, err)
+
+				m, err = v.FingerModel([]Finger{{Keys: "qwe"}})
+				assert.NoError(t, err)
+				_, err = TypingCost(m, []string{"qwa"})
+				assert.Error(t, err)
+			})
+		})
+	})
+}
@@ -3,12 +3,21 @@ package processor
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"sort"
+	"strings"
 )
 
 type vocab struct {
 	distanceArray []int
+	startArray    []int    // distances from the start key to every letter, nil if not set
+	endArray      []int    // distances from every letter to the end key, nil if not set
+	pressArray    []int    // cost of pressing every letter, nil if not set
+	excluded      []bool   // letters of broken or invisible keys, words with them are not read; nil if not set
+	gesture       *Gesture // swipe cost of words replacing distanceArray inside words, nil if not set
+	exclusion     ExclusionReport
 	minLen        int
 	maxLen        int
 	wordCnt       uint8
@@ -35,21 +44,110 @@ func (v *vocab) PathLen(word string) (int, error) {
 		return 0, nil
 	}
 
-	if word[l-1] < 'a' || word[l-1] > 'z' {
-		return 0, fmt.Errorf("wrong symbol: %v", word[l-1])
-	}
+	if v.gesture != nil {
+		n, err := v.gesture.Cost(word)
+		if err != nil {
+			return 0, err
+		}
+		sum = n
+	} else {
+		if word[l-1] < 'a' || word[l-1] > 'z' {
+			return 0, fmt.Errorf("wrong symbol: %v", word[l-1])
+		}
+
+		for i := 0; i < (l - 1); i++ {
+			if word[i] < 'a' || word[i] > 'z' {
+				return 0, fmt.Errorf("wrong symbol: %v", word[i])
+			}
+			pathLen := v.distanceArray[getIndexBigram(word[i], word[i+1])]
 
-	for i := 0; i < (l - 1); i++ {
-		if word[i] < 'a' || word[i] > 'z' {
-			return 0, fmt.Errorf("wrong symbol: %v", word[i])
+			sum += pathLen
 		}
-		pathLen := v.distanceArray[getIndexBigram(word[i], word[i+1])]
+	}
 
-		sum += pathLen
+	if v.pressArray != nil {
+		for i := 0; i < l; i++ {
+			sum += v.pressArray[symbolOffset(word[i])]
+		}
 	}
 	return sum, nil
 }
 
+// SetPressCost sets the cost of pressing every letter, it is added to PathLen per keystroke
+func (v *vocab) SetPressCost(cost []int) {
+	v.pressArray = cost
+}
+
+// SetExcludedKeys sets broken or invisible keys, ReadFile drops words containing them.
+// Keys which are not letters (enter, punctuation) are not used by words and ignored
+func (v *vocab) SetExcludedKeys(keys []string) {
+	v.excluded = nil
+	for _, k := range keys {
+		if len(k) != 1 || k[0] < 'a' || k[0] > 'z' {
+			continue
+		}
+		if v.excluded == nil {
+			v.excluded = make([]bool, 32)
+		}
+		v.excluded[symbolOffset(k[0])] = true
+	}
+}
+
+// Exclusion returns how many words were dropped by the last ReadFile because of excluded keys
+func (v *vocab) Exclusion() ExclusionReport {
+	return v.exclusion
+}
+
+// excludedLetters returns excluded letters of the word, every letter once
+func (v *vocab) excludedLetters(word string) []string {
+	var res []string
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' || !v.excluded[symbolOffset(word[i])] {
+			continue
+		}
+		if !strings.Contains(word[:i], word[i:i+1]) {
+			res = append(res, word[i:i+1])
+		}
+	}
+	return res
+}
+
+// SetStartKey sets distances from the key where the finger rests before typing
+func (v *vocab) SetStartKey(dist []int) {
+	v.startArray = dist
+}
+
+// SetEndKey sets distances to the key pressed after the password (e.g. Enter)
+func (v *vocab) SetEndKey(dist []int) {
+	v.endArray = dist
+}
+
+// StartPathLen returns the path from the start key to the first letter of the word
+func (v *vocab) StartPathLen(word string) int {
+	if v.startArray == nil || len(word) == 0 {
+		return 0
+	}
+	return v.startArray[symbolOffset(word[0])]
+}
+
+// EndPathLen returns the path from the last letter of the word to the end key
+func (v *vocab) EndPathLen(word string) int {
+	l := len(word)
+	if v.endArray == nil || l == 0 {
+		return 0
+	}
+	return v.endArray[symbolOffset(word[l-1])]
+}
+
+// FullPathLen is PathLen of the whole password including moves from the start key and to the end key
+func (v *vocab) FullPathLen(password string) (int, error) {
+	n, err := v.PathLen(password)
+	if err != nil {
+		return 0, err
+	}
+	return v.StartPathLen(password) + n + v.EndPathLen(password), nil
+}
+
 func (v *vocab) GapPathLen(word1, word2 string) (int, error) {
 	l1, l2 := len(word1), len(word2)
 	if l1 < 1 || l2 < 1 {
@@ -61,26 +159,68 @@ func (v *vocab) GapPathLen(word1, word2 string) (int, error) {
 }
 
 func (v *vocab) ReadFile(fileName string, needSort bool) ([]*wordMetric, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("file name:%s", fileName)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return v.read(file, needSort, nil)
+}
+
+// ReadFS reads vocabulary from the file system, e.g. embedded into the binary
+func (v *vocab) ReadFS(fsys fs.FS, fileName string, needSort bool) ([]*wordMetric, error) {
+	file, err := fsys.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("file name:%s", fileName)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return v.read(file, needSort, nil)
+}
+
+// read returns words of the reader without words with excluded keys. Words rejected by PathLen are passed
+// to reject, its error stops reading, with nil reject the error of PathLen is returned
+func (v *vocab) read(r io.Reader, needSort bool, reject func(word string, err error) error) ([]*wordMetric, error) {
 	var (
 		word    string
 		pathLen int
+		err     error
 		res     []*wordMetric
 	)
 
-	file, err := os.Open(fileName)
-	if err != nil {
-		return nil, fmt.Errorf("file name:%s", fileName)
-	}
+	v.exclusion = ExclusionReport{ByKey: make(map[string]int)}
 
-	Scanner := bufio.NewScanner(file)
+	Scanner := bufio.NewScanner(r)
 	Scanner.Split(bufio.ScanWords)
 
 	for Scanner.Scan() {
 		word = Scanner.Text()
+		v.exclusion.Words++
+
+		if v.excluded != nil {
+			if letters := v.excludedLetters(word); len(letters) > 0 {
+				v.exclusion.Dropped++
+				for _, l := range letters {
+					v.exclusion.ByKey[l]++
+				}
+				continue
+			}
+		}
 
 		pathLen, err = v.PathLen(word)
 		if err != nil {
-			return nil, err
+			if reject == nil {
+				return nil, err
+			}
+			if err = reject(word, err); err != nil {
+				return nil, err
+			}
+			continue
 		}
 
 		wm := wordMetric{
@@ -101,3 +241,105 @@ func (v *vocab) ReadFile(fileName string, needSort bool) ([]*wordMetric, error)
 	}
 	return res, nil
 }
+
+// Letters returns letters used by words in alphabetical order
+func (v *vocab) Letters(items []*wordMetric) string {
+	var used [32]bool
+	for _, wm := range items {
+		for i := 0; i < len(wm.word); i++ {
+			used[symbolOffset(wm.word[i])&0x1F] = true
+		}
+	}
+
+	res := make([]byte, 0, len(used))
+	for i, ok := range used {
+		if ok {
+			res = append(res, 'a'+byte(i))
+		}
+	}
+	return string(res)
+}
+
+// PathReport returns mean path length of words and costs of their bigrams
+func (v *vocab) PathReport(items []*wordMetric) PathReport {
+	r := PathReport{Words: len(items), Bigrams: make(map[int]int)}
+	if len(items) == 0 {
+		return r
+	}
+
+	sum := 0
+	for _, wm := range items {
+		sum += wm.pathLen
+		for i := 0; i+1 < len(wm.word); i++ {
+			r.Bigrams[v.distanceArray[getIndexBigram(wm.word[i], wm.word[i+1])]]++
+		}
+	}
+	r.MeanPathLen = float64(sum) / float64(len(items))
+	return r
+}
+
+// Stats reads the vocabulary like ReadFS but words rejected by PathLen are counted instead of failing,
+// cheapest is the count of words with the lowest PathLen kept for every length
+func (v *vocab) Stats(fsys fs.FS, fileName string, cheapest int) (VocabularyStats, error) {
+	s := VocabularyStats{
+		ByLength: make(map[int]int),
+		PathLens: make(map[int]int),
+		Cheapest: make(map[int][]WordCost),
+		First:    make(map[string]int),
+		Last:     make(map[string]int),
+	}
+
+	file, err := fsys.Open(fileName)
+	if err != nil {
+		return s, fmt.Errorf("file name:%s", fileName)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	items, err := v.read(file, false, func(string, error) error {
+		s.Rejected++
+		return nil
+	})
+	if err != nil {
+		return s, err
+	}
+	s.Words = v.exclusion.Words
+	s.Excluded = v.exclusion.Dropped
+
+	var sum int
+	for _, wm := range items {
+		l := len(wm.word)
+		sum += wm.pathLen
+		s.ByLength[l]++
+		s.PathLens[wm.pathLen]++
+		s.First[wm.word[:1]]++
+		s.Last[wm.word[l-1:]]++
+		s.Cheapest[l] = insertCheapest(s.Cheapest[l], WordCost{Word: wm.word, PathLen: wm.pathLen}, cheapest)
+	}
+
+	if len(items) > 0 {
+		s.MeanPathLen = float64(sum) / float64(len(items))
+	}
+	return s, nil
+}
+
+// insertCheapest adds the word keeping words sorted by PathLen and the word, only count cheapest words are kept,
+// repeated words are kept once
+func insertCheapest(words []WordCost, w WordCost, count int) []WordCost {
+	i := sort.Search(len(words), func(i int) bool {
+		return words[i].PathLen > w.PathLen || (words[i].PathLen == w.PathLen && words[i].Word > w.Word)
+	})
+	if i >= count || (i > 0 && words[i-1] == w) {
+		return words
+	}
+
+	words = append(words, WordCost{})
+	copy(words[i+1:], words[i:])
+	words[i] = w
+
+	if len(words) > count {
+		words = words[:count]
+	}
+	return words
+}
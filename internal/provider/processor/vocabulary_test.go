@@ -4,6 +4,7 @@
 package processor
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -94,6 +95,66 @@ func TestNewVocabulary(t *testing.T) {
 
 		})
 
+		t.Run("StartPathLen & EndPathLen & FullPathLen", func(t *testing.T) {
+			m := getKeyboardMapForTests()
+			vk := NewVocab(dist, 0, 0, 0)
+
+			assert.Equal(t, 0, vk.StartPathLen(w3))
+			assert.Equal(t, 0, vk.EndPathLen(w3))
+
+			n, err = vk.FullPathLen(w3)
+			assert.NoError(t, err)
+			assert.Equal(t, 6, n)
+
+			startDist, err := graph.FromKeyDistanceArray(m, "g")
+			assert.NoError(t, err)
+			vk.SetStartKey(startDist)
+
+			endDist, err := graph.ToKeyDistanceArray(m, "l")
+			assert.NoError(t, err)
+			vk.SetEndKey(endDist)
+
+			// g -> t
+			assert.Equal(t, 1, vk.StartPathLen(w3))
+			// e -> l
+			assert.Equal(t, 7, vk.EndPathLen(w3))
+			assert.Equal(t, 0, vk.StartPathLen(""))
+			assert.Equal(t, 0, vk.EndPathLen(""))
+
+			n, err = vk.FullPathLen(w3)
+			assert.NoError(t, err)
+			assert.Equal(t, 1+6+7, n)
+
+			_, err = vk.FullPathLen("?)")
+			assert.Error(t, err)
+		})
+
+		t.Run("PathLen with press cost", func(t *testing.T) {
+			vp := NewVocab(dist, 0, 0, 0)
+			vp.SetPressCost(graph.KeyWeightArray(map[string]int{"q": 2, "p": 2, "t": 1}))
+
+			n, err = vp.PathLen(w1)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, n)
+
+			// 6 hops + t
+			n, err = vp.PathLen(w3)
+			assert.NoError(t, err)
+			assert.Equal(t, 7, n)
+
+			n, err = vp.PathLen("pq")
+			assert.NoError(t, err)
+			assert.Equal(t, 9+2+2, n)
+
+			// gap does not press anything
+			n, err = vp.GapPathLen("q", "a")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			n, err = vp.PathLen("p1")
+			assert.Error(t, err)
+		})
+
 		t.Run("ReadFile", func(t *testing.T) {
 			wordMetrics, err = v.ReadFile("testdata/test.txt", true)
 			assert.NoError(t, err)
@@ -109,10 +170,98 @@ func TestNewVocabulary(t *testing.T) {
 				assert.Equal(t, true, length <= len(wm.word))
 			}
 		})
+
+		t.Run("ReadFS", func(t *testing.T) {
+			wm, err := v.ReadFS(os.DirFS("testdata"), "test.txt", true)
+			assert.NoError(t, err)
+			assert.Equal(t, wordMetrics, wm)
+
+			_, err = v.ReadFS(os.DirFS("testdata"), "nonexistent.txt", true)
+			assert.Error(t, err)
+		})
+
+		t.Run("ReadFile with excluded keys", func(t *testing.T) {
+			ve := NewVocab(dist, 0, 0, 0)
+			ve.SetExcludedKeys([]string{"o", "t", "enter"})
+
+			wm, err := ve.ReadFile("testdata/test.txt", true)
+			assert.NoError(t, err)
+			assert.Equal(t, "abeik", ve.Letters(wm))
+
+			r := ve.Exclusion()
+			assert.Equal(t, 5, r.Words)
+			assert.Equal(t, 3, r.Dropped)
+			assert.Equal(t, map[string]int{"o": 3, "t": 2}, r.ByKey)
+			assert.Equal(t, 0.6, r.Lost())
+
+			ve.SetExcludedKeys(nil)
+			wm, err = ve.ReadFile("testdata/test.txt", true)
+			assert.NoError(t, err)
+			assert.Equal(t, 5, len(wm))
+			assert.Equal(t, 0, ve.Exclusion().Dropped)
+		})
+
+		t.Run("Letters", func(t *testing.T) {
+			assert.Equal(t, "abefikmnorst", v.Letters(wordMetrics))
+			assert.Equal(t, "", v.Letters(nil))
+		})
+
+		t.Run("PathReport", func(t *testing.T) {
+			r := v.PathReport(wordMetrics)
+			assert.Equal(t, 5, r.Words)
+			assert.InDelta(t, 17.6, r.MeanPathLen, 1e-9)
+			assert.Equal(t, 23, r.BigramCount())
+			assert.Equal(t, 6, r.Bigrams[3])
+			assert.Equal(t, 1, r.BigramQuantile(0))
+			assert.Equal(t, 4, r.BigramQuantile(0.5))
+			assert.Equal(t, 8, r.BigramQuantile(1))
+
+			r = v.PathReport(nil)
+			assert.Equal(t, 0, r.Words)
+			assert.Equal(t, 0, r.BigramQuantile(0.5))
+		})
+
+		t.Run("Stats", func(t *testing.T) {
+			s, err := v.Stats(os.DirFS("testdata"), "stats.txt", 2)
+			assert.NoError(t, err)
+			assert.Equal(t, 10, s.Words)
+			//don't, Rome, bike2
+			assert.Equal(t, 3, s.Rejected)
+			assert.InDelta(t, 0.3, s.RejectedPart(), 1e-9)
+			assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1, 4: 3}, s.ByLength)
+			assert.Equal(t, map[int]int{0: 1, 5: 2, 8: 2, 10: 1, 15: 1}, s.PathLens)
+			assert.InDelta(t, 51.0/7, s.MeanPathLen, 1e-9)
+			assert.Equal(t, []WordCost{{"of", 5}}, s.Cheapest[2])
+			assert.Equal(t, []WordCost{{"cafe", 8}, {"bike", 10}}, s.Cheapest[4])
+			assert.Equal(t, 3, s.First["o"])
+			assert.Equal(t, 2, s.Last["e"])
+
+			ve := NewVocab(dist, 0, 0, 0)
+			ve.SetExcludedKeys([]string{"z"})
+			s, err = ve.Stats(os.DirFS("testdata"), "stats.txt", 2)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, s.Excluded)
+			assert.Equal(t, 0, s.ByLength[3])
+
+			//words are checked for excluded keys first, as by ReadFS
+			ve.SetExcludedKeys([]string{"n"})
+			s, err = ve.Stats(os.DirFS("testdata"), "stats.txt", 2)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, s.Excluded)
+			assert.Equal(t, 2, s.Rejected)
+			assert.Equal(t, s.Excluded, ve.Exclusion().Dropped)
+
+			_, err = v.Stats(os.DirFS("testdata"), "nonexistent.txt", 2)
+			assert.Error(t, err)
+		})
 	})
 }
 
 func getDistanceMapForTests() []int {
+	return graph.BigramDistanceArray(getKeyboardMapForTests())
+}
+
+func getKeyboardMapForTests() map[string]map[string]int {
 	hash := func(v graph.Vertex) string {
 		return v.Name
 	}
@@ -185,6 +334,5 @@ func getDistanceMapForTests() []int {
 	_ = g.AddEdge("k", "m")
 
 	m, _ := g.WFI(20)
-	dist := graph.BigramDistanceArray(m)
-	return dist
+	return m
 }